@@ -0,0 +1,151 @@
+package benchmarks
+
+import (
+	"io"
+	"os"
+
+	apex "github.com/apex/log"
+	kitlog "github.com/go-kit/log"
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slog"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// libraryLoggers holds a logger of every library writing JSON records into the
+// same writer at debug level. logy's is its root logger, writing wherever logy
+// was last configured to.
+type libraryLoggers struct {
+	logy    *logy.Logger
+	slog    slog.Logger
+	zap     *zap.Logger
+	zerolog zerolog.Logger
+	apex    *apex.Logger
+	kit     kitlog.Logger
+	log15   log15.Logger
+	logrus  *logrus.Logger
+}
+
+func newLibraryLoggers(w io.Writer) libraryLoggers {
+	return libraryLoggers{
+		logy:    logy.Get(),
+		slog:    slog.New(slog.NewJSONHandler(w)),
+		zap:     newZapLoggerTo(w, zap.DebugLevel),
+		zerolog: newZerologTo(w),
+		apex:    newApexLogTo(w),
+		kit:     newKitLogTo(w),
+		log15:   newLog15To(w),
+		logrus:  newLogrusTo(w),
+	}
+}
+
+// switchWriter writes into the writer it currently holds, so the loggers of a
+// scenario can be built once and still each log into a buffer of its own.
+type switchWriter struct {
+	io.Writer
+}
+
+// writerLogger is one library set up to write JSON records carrying the ten
+// fake context fields into an arbitrary writer.
+type writerLogger struct {
+	name string
+	// piped tells whether the records reach w through the pipe of
+	// redirectLogy rather than being written to it by the library.
+	piped bool
+	// new returns a function logging a single record into w, and a function
+	// to call once done with the logger.
+	new func(w io.Writer) (log func(), stop func())
+}
+
+// newLogyFileLogger is the Logy entry of writerLoggers writing straight into
+// f, for the scenarios needing logy's own writes rather than the pipe's.
+func newLogyFileLogger(f *os.File) (log func(), stop func()) {
+	stop = loadLogyInto(f, &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: newLogyJsonConsole()})
+	logger := logy.Get()
+	ctx := fakeLogyContext()
+	return func() {
+		logger.I(ctx, getMessage(0))
+	}, stop
+}
+
+func writerLoggers() []writerLogger {
+	return []writerLogger{
+		{
+			name:  "Logy.Pipe",
+			piped: true,
+			new: func(w io.Writer) (func(), func()) {
+				stop := redirectLogy(w, &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: newLogyJsonConsole()})
+				logger := logy.Get()
+				ctx := fakeLogyContext()
+				return func() {
+					logger.I(ctx, getMessage(0))
+				}, stop
+			},
+		},
+		{
+			name: "exp/slog",
+			new: func(w io.Writer) (func(), func()) {
+				logger := slog.New(slog.NewJSONHandler(w)).With(fakeSugarFields()...)
+				return func() {
+					logger.Info(getMessage(0))
+				}, func() {}
+			},
+		},
+		{
+			name: "Zap",
+			new: func(w io.Writer) (func(), func()) {
+				logger := newZapLoggerTo(w, zap.DebugLevel).With(fakeFields()...)
+				return func() {
+					logger.Info(getMessage(0))
+				}, func() { _ = logger.Sync() }
+			},
+		},
+		{
+			name: "rs/zerolog",
+			new: func(w io.Writer) (func(), func()) {
+				logger := fakeZerologContext(newZerologTo(w).With()).Logger()
+				return func() {
+					logger.Info().Msg(getMessage(0))
+				}, func() {}
+			},
+		},
+		{
+			name: "apex/log",
+			new: func(w io.Writer) (func(), func()) {
+				logger := newApexLogTo(w).WithFields(fakeApexFields())
+				return func() {
+					logger.Info(getMessage(0))
+				}, func() {}
+			},
+		},
+		{
+			name: "go-kit/kit/log",
+			new: func(w io.Writer) (func(), func()) {
+				logger := newKitLogTo(w, fakeSugarFields()...)
+				return func() {
+					_ = logger.Log("msg", getMessage(0))
+				}, func() {}
+			},
+		},
+		{
+			name: "inconshreveable/log15",
+			new: func(w io.Writer) (func(), func()) {
+				logger := newLog15To(w).New(fakeSugarFields()...)
+				return func() {
+					logger.Info(getMessage(0))
+				}, func() {}
+			},
+		},
+		{
+			name: "sirupsen/logrus",
+			new: func(w io.Writer) (func(), func()) {
+				logger := newLogrusTo(w).WithFields(fakeLogrusFields())
+				return func() {
+					logger.Info(getMessage(0))
+				}, func() {}
+			},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"github.com/procyon-projects/logy"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slog"
+)
+
+// _allocBudgets are the most allocations per record the hot paths may make,
+// keep them in line with the README.
+var _allocBudgets = []struct {
+	name   string
+	budget float64
+	// setup returns the function logging a single record.
+	setup func() func()
+}{
+	{"Logy.Info", 0, func() func() {
+		loadLogyJsonDiscard()
+		logger := logy.Get()
+		return func() { logger.Info(getMessage(0)) }
+	}},
+	{"Logy.I/TenContextFields", 0, func() func() {
+		loadLogyJsonDiscard()
+		logger := logy.Get()
+		ctx := fakeLogyContext()
+		return func() { logger.I(ctx, getMessage(0)) }
+	}},
+	{"Logy.Info/Disabled", 0, func() func() {
+		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelError, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard, Enabled: true}})
+		logger := logy.Get()
+		return func() { logger.Info(getMessage(0)) }
+	}},
+	{"exp/slog", 0, func() func() {
+		logger := slog.New(discardHandler{})
+		return func() { logger.Info(getMessage(0)) }
+	}},
+	{"Zap", 0, func() func() {
+		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...)
+		return func() { logger.Info(getMessage(0)) }
+	}},
+	{"Zap.Check", 0, func() func() {
+		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...)
+		return func() {
+			if ce := logger.Check(zap.InfoLevel, getMessage(0)); ce != nil {
+				ce.Write()
+			}
+		}
+	}},
+	{"Zap.Check/Disabled", 0, func() func() {
+		logger := newZapLogger(zap.ErrorLevel)
+		return func() {
+			if ce := logger.Check(zap.InfoLevel, getMessage(0)); ce != nil {
+				ce.Write()
+			}
+		}
+	}},
+	{"Zap.Sugar", 1, func() func() {
+		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...).Sugar()
+		return func() { logger.Info(getMessage(0)) }
+	}},
+	{"rs/zerolog", 0, func() func() {
+		logger := fakeZerologContext(newZerolog().With()).Logger()
+		return func() { logger.Info().Msg(getMessage(0)) }
+	}},
+}
+
+func loadLogyJsonDiscard() {
+	console := newLogyJsonConsole()
+	console.Target = logy.TargetDiscard
+	_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: console})
+}
+
+func TestAllocBudgets(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector makes sync.Pool drop items, so pooled buffers get allocated")
+	}
+	for _, tt := range _allocBudgets {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			log := tt.setup()
+			if got := testing.AllocsPerRun(1000, log); got > tt.budget {
+				t.Errorf("got %v allocs per record, budget is %v", got, tt.budget)
+			}
+		})
+	}
+}
@@ -7,16 +7,19 @@ import (
 	"github.com/apex/log/handlers/json"
 )
 
-func newDisabledApexLog() *log.Logger {
-	return &log.Logger{
-		Handler: json.New(io.Discard),
-		Level:   log.ErrorLevel,
-	}
+func newDisabledApexLog(w io.Writer) *log.Logger {
+	logger := newApexLogTo(w)
+	logger.Level = log.ErrorLevel
+	return logger
 }
 
 func newApexLog() *log.Logger {
+	return newApexLogTo(io.Discard)
+}
+
+func newApexLogTo(w io.Writer) *log.Logger {
 	return &log.Logger{
-		Handler: json.New(io.Discard),
+		Handler: json.New(w),
 		Level:   log.DebugLevel,
 	}
 }
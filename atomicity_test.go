@@ -0,0 +1,140 @@
+package benchmarks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// shortWriter captures what is written to it a few bytes at a time, yielding
+// between the pieces the way a partial write to a file or socket would. Records
+// written by unsynchronized concurrent calls end up interleaved.
+type shortWriter struct {
+	chunk int
+	mu    sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (w *shortWriter) Write(b []byte) (int, error) {
+	for p := b; len(p) > 0; {
+		n := w.chunk
+		if n > len(p) {
+			n = len(p)
+		}
+		w.mu.Lock()
+		w.buf.Write(p[:n])
+		w.mu.Unlock()
+		p = p[n:]
+		runtime.Gosched()
+	}
+	return len(b), nil
+}
+
+// torn returns how many of the captured lines aren't a single JSON record,
+// along with the number of valid ones.
+func (w *shortWriter) torn() (torn, valid int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return tornLines(w.buf.Bytes())
+}
+
+// tornLines returns how many of the lines of b aren't a single JSON record,
+// along with the number of valid ones.
+func tornLines(b []byte) (torn, valid int) {
+	for _, line := range bytes.Split(b, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]interface{}
+		if json.Unmarshal(line, &record) != nil {
+			torn++
+		} else {
+			valid++
+		}
+	}
+	return torn, valid
+}
+
+// _atomicWriters lists the libraries serializing the writes to their writer.
+// The others leave it to the writer, zap expects a zapcore.Lock'ed one and
+// zerolog and go-kit a SyncWriter, so they are only reported.
+var _atomicWriters = map[string]bool{
+	"Logy":                  true,
+	"exp/slog":              true,
+	"apex/log":              true,
+	"inconshreveable/log15": true,
+	"sirupsen/logrus":       true,
+}
+
+// logConcurrently calls log records times from each of the goroutines.
+func logConcurrently(goroutines, records int, log func()) {
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < records; j++ {
+				log()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// checkAtomicity reports the torn lines out of the records logged, failing
+// the test for the libraries expected to be line atomic.
+func checkAtomicity(t *testing.T, name string, torn, valid, records int) {
+	t.Helper()
+	switch {
+	case torn == 0 && valid != records:
+		t.Errorf("got %d records, want %d", valid, records)
+	case torn > 0 && _atomicWriters[name]:
+		t.Errorf("got %d torn or merged lines out of %d records", torn, records)
+	case torn > 0:
+		t.Logf("not line atomic: %d torn or merged lines out of %d records", torn, records)
+	}
+}
+
+func TestLineAtomicity(t *testing.T) {
+	const records = 200
+	goroutines := 4 * runtime.GOMAXPROCS(0)
+
+	// logy can't be given a writer, it writes straight into a file instead of
+	// going through the pipe, whose copier would serialize the records.
+	t.Run("Logy", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "atomicity")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		log, stop := newLogyFileLogger(f)
+		logConcurrently(goroutines, records, log)
+		stop()
+
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		torn, valid := tornLines(b)
+		checkAtomicity(t, "Logy", torn, valid, goroutines*records)
+	})
+
+	for _, l := range writerLoggers() {
+		if l.piped {
+			continue
+		}
+		l := l
+		t.Run(l.name, func(t *testing.T) {
+			w := &shortWriter{chunk: 64}
+			log, stop := l.new(w)
+			logConcurrently(goroutines, records, log)
+			stop()
+
+			torn, valid := w.torn()
+			checkAtomicity(t, l.name, torn, valid, goroutines*records)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Command benchgate compares benchmark results against a baseline recorded
+// with -update, by default into benchgate.json, and exits with status 1 when
+// the gated benchmarks regress, or 2 when none of them is in the baseline. It
+// runs the benchmarks itself with -bench, or reads go test -bench output from
+// the files given as arguments or from stdin:
+//
+//	go run ./cmd/benchgate -bench . -update   # record the baseline
+//	go run ./cmd/benchgate -bench .           # after bumping logy in go.mod
+//	go test -run XXX -bench . -count 10 -benchmem | go run ./cmd/benchgate
+//
+// A metric regresses when its median grew by more than -threshold percent and
+// the Mann-Whitney U test tells the runs apart with p < -alpha, so use -count
+// of 5 or more for a meaningful gate.
+//
+// The baseline holds the machine it was recorded on, and benchgate warns when
+// the compared run comes from another one, as its results then differ whatever
+// the logy version.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// baseline is the result set recorded with -update and compared against.
+type baseline struct {
+	// Env is the configuration of the recorded run, the machine and the
+	// module versions.
+	Env        map[string]string `json:"env,omitempty"`
+	Benchmarks results           `json:"benchmarks"`
+}
+
+// _machineKeys are the configuration lines telling machines apart.
+var _machineKeys = []string{"goos", "goarch", "cpu", "cpu-model", "gomaxprocs", "go"}
+
+type config struct {
+	baseline  string
+	bench     string
+	count     int
+	filter    *regexp.Regexp
+	threshold float64
+	alpha     float64
+	update    bool
+}
+
+func main() {
+	var cfg config
+	var filter string
+	flag.StringVar(&cfg.baseline, "baseline", "benchgate.json", "baseline `file`")
+	flag.StringVar(&cfg.bench, "bench", "", "run the benchmarks matching `regexp` instead of reading their output")
+	flag.IntVar(&cfg.count, "count", 6, "runs of each benchmark with -bench")
+	flag.StringVar(&filter, "filter", "/Logy", "gate the benchmarks matching `regexp`, the others are only reported")
+	flag.Float64Var(&cfg.threshold, "threshold", 5, "tolerated growth of a median, in `percent`")
+	flag.Float64Var(&cfg.alpha, "alpha", 0.05, "significance level of the Mann-Whitney U test")
+	flag.BoolVar(&cfg.update, "update", false, "write the results into the baseline instead of comparing")
+	flag.Parse()
+
+	var err error
+	if cfg.filter, err = regexp.Compile(filter); err != nil {
+		fatal(err)
+	}
+
+	current, env, err := readResults(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	if len(current) == 0 {
+		fatal(fmt.Errorf("no benchmark results"))
+	}
+
+	if cfg.update {
+		if err := writeBaseline(cfg.baseline, current, env); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	base, err := readBaseline(cfg.baseline)
+	if err != nil {
+		fatal(err)
+	}
+	warnMachine(os.Stderr, base.Env, env)
+	diffs := compare(cfg, base.Benchmarks, current)
+	printDiffs(os.Stdout, diffs)
+	gated := 0
+	for _, d := range diffs {
+		if d.regressed {
+			os.Exit(1)
+		}
+		if d.gated {
+			gated++
+		}
+	}
+	if gated == 0 {
+		fatal(fmt.Errorf("no results matching -filter %s are in the baseline %s, nothing was gated", filter, cfg.baseline))
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "benchgate:", err)
+	os.Exit(2)
+}
+
+func readResults(cfg config) (results, map[string]string, error) {
+	if cfg.bench != "" {
+		var out bytes.Buffer
+		cmd := exec.Command("go", "test", "-run", "XXX", "-bench", cfg.bench, "-benchmem", "-count", strconv.Itoa(cfg.count), ".")
+		cmd.Stdout, cmd.Stderr = io.MultiWriter(&out, os.Stderr), os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, nil, err
+		}
+		return parseResults(&out)
+	}
+
+	if flag.NArg() == 0 {
+		return parseResults(os.Stdin)
+	}
+	all := make(results)
+	env := make(map[string]string)
+	for _, name := range flag.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		res, config, err := parseResults(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		for key, value := range config {
+			env[key] = value
+		}
+		for bench, metrics := range res {
+			if all[bench] == nil {
+				all[bench] = make(map[string]samples)
+			}
+			for unit, s := range metrics {
+				all[bench][unit] = append(all[bench][unit], s...)
+			}
+		}
+	}
+	return all, env, nil
+}
+
+func readBaseline(path string) (baseline, error) {
+	var b baseline
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, fmt.Errorf("%w, record one with -update", err)
+	}
+	err = json.Unmarshal(data, &b)
+	return b, err
+}
+
+func writeBaseline(path string, res results, env map[string]string) error {
+	data, err := json.MarshalIndent(baseline{Env: env, Benchmarks: res}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// warnMachine tells when the current run comes from another machine than the
+// baseline. Baselines recorded before they held it are taken as they are.
+func warnMachine(w io.Writer, base, current map[string]string) {
+	if len(base) == 0 {
+		return
+	}
+	for _, key := range _machineKeys {
+		if base[key] != current[key] {
+			fmt.Fprintf(w, "benchgate: the baseline was recorded with %s %q, this run has %q, compare with care\n", key, base[key], current[key])
+		}
+	}
+}
+
+// diff is the comparison of one metric of one benchmark.
+type diff struct {
+	name        string
+	unit        string
+	old, new    float64
+	delta       float64 // relative change of the median, in percent
+	p           float64
+	significant bool
+	gated       bool
+	regressed   bool
+}
+
+func compare(cfg config, base, current results) []diff {
+	var diffs []diff
+	for name, metrics := range current {
+		for _, unit := range _gatedUnits {
+			oldSamples, newSamples := base[name][unit], metrics[unit]
+			if len(oldSamples) == 0 || len(newSamples) == 0 {
+				continue
+			}
+			d := diff{
+				name:  name,
+				unit:  unit,
+				old:   median(oldSamples),
+				new:   median(newSamples),
+				p:     mannWhitney(oldSamples, newSamples),
+				gated: cfg.filter.MatchString(name),
+			}
+			d.delta = relativeChange(d.old, d.new)
+			d.significant = d.p < cfg.alpha
+			d.regressed = d.gated && d.significant && d.delta > cfg.threshold
+			diffs = append(diffs, d)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].name != diffs[j].name {
+			return diffs[i].name < diffs[j].name
+		}
+		return diffs[i].unit < diffs[j].unit
+	})
+	return diffs
+}
+
+func relativeChange(old, new float64) float64 {
+	switch {
+	case old == new:
+		return 0
+	case old == 0:
+		return math.Inf(1)
+	}
+	return (new - old) / old * 100
+}
+
+func printDiffs(w io.Writer, diffs []diff) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tmetric\tbaseline\tcurrent\tdelta\tp\t")
+	for _, d := range diffs {
+		delta := "~"
+		if d.significant {
+			delta = fmt.Sprintf("%+.1f%%", d.delta)
+		}
+		verdict := ""
+		switch {
+		case d.regressed:
+			verdict = "REGRESSION"
+		case !d.gated:
+			verdict = "(not gated)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.4g\t%.4g\t%s\tp=%.3f\t%s\n", d.name, d.unit, d.old, d.new, delta, d.p, verdict)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const _output = `goos: linux
+goarch: amd64
+BenchmarkWithContext/Logy-8         	 1000000	        85.29 ns/op	       0 B/op	       0 allocs/op
+BenchmarkWithContext/Logy-8         	 1000000	        86.10 ns/op	       0 B/op	       0 allocs/op
+BenchmarkWithContext/Zap-8          	 1000000	        92.50 ns/op	      1574 bytes/op	       0 B/op	       0 allocs/op
+    scenario_bench_test.go:12: Logging with some accumulated context.
+PASS
+`
+
+func TestParseResults(t *testing.T) {
+	got, env, err := parseResults(strings.NewReader(_output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["goos"] != "linux" || env["goarch"] != "amd64" {
+		t.Errorf("got env %v, want goos linux and goarch amd64", env)
+	}
+	want := results{
+		"BenchmarkWithContext/Logy": {"ns/op": {85.29, 86.10}, "B/op": {0, 0}, "allocs/op": {0, 0}},
+		"BenchmarkWithContext/Zap":  {"ns/op": {92.50}, "B/op": {0}, "allocs/op": {0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMannWhitney(t *testing.T) {
+	tests := []struct {
+		x, y   samples
+		maxP   float64
+		minP   float64
+		reason string
+	}{
+		{samples{1, 2, 3, 4, 5}, samples{6, 7, 8, 9, 10}, 0.05, 0, "separated samples"},
+		{samples{1, 1, 1, 1, 1}, samples{1, 1, 1, 1, 1}, 1, 1, "identical samples"},
+		{samples{1, 3, 5, 7, 9}, samples{2, 4, 6, 8, 10}, 1, 0.5, "interleaved samples"},
+		{samples{1}, samples{2}, 1, 1, "single runs"},
+		{nil, samples{2}, 1, 1, "no baseline"},
+	}
+	for _, tt := range tests {
+		if p := mannWhitney(tt.x, tt.y); p > tt.maxP || p < tt.minP {
+			t.Errorf("%s: got p=%v, want it in [%v, %v]", tt.reason, p, tt.minP, tt.maxP)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	cfg := config{filter: regexp.MustCompile("/Logy"), threshold: 5, alpha: 0.05}
+	base := results{
+		"BenchmarkWithContext/Logy": {"ns/op": {85, 86, 84, 85, 86}, "allocs/op": {0, 0, 0, 0, 0}},
+		"BenchmarkWithContext/Zap":  {"ns/op": {90, 91, 92, 90, 91}},
+	}
+	current := results{
+		"BenchmarkWithContext/Logy": {"ns/op": {95, 96, 97, 95, 96}, "allocs/op": {0, 0, 0, 0, 0}},
+		"BenchmarkWithContext/Zap":  {"ns/op": {120, 121, 122, 120, 121}},
+	}
+
+	var regressed []string
+	for _, d := range compare(cfg, base, current) {
+		if d.regressed {
+			regressed = append(regressed, d.name+" "+d.unit)
+		}
+	}
+	if want := []string{"BenchmarkWithContext/Logy ns/op"}; !reflect.DeepEqual(regressed, want) {
+		t.Errorf("got regressions %v, want %v", regressed, want)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// mannWhitney returns the two-sided p-value of the Mann-Whitney U test for
+// samples x and y, using the normal approximation with tie correction. It is 1
+// when the samples can't be told apart, including when either is empty.
+func mannWhitney(x, y samples) float64 {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type rank struct {
+		value float64
+		fromX bool
+	}
+	all := make([]rank, 0, len(x)+len(y))
+	for _, v := range x {
+		all = append(all, rank{v, true})
+	}
+	for _, v := range y {
+		all = append(all, rank{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Tied values share the mean of the ranks they span.
+	var rankSumX, ties float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		mean := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromX {
+				rankSumX += mean
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	u := rankSumX - n1*(n1+1)/2
+	n := n1 + n2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * (n + 1 - ties/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
+
+func median(s samples) float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	sorted := append(samples(nil), s...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
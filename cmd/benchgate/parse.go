@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+// _gatedUnits are the metrics kept in the baseline and compared.
+var _gatedUnits = []string{"ns/op", "B/op", "allocs/op"}
+
+// samples holds every value measured for one metric, one per -count run.
+type samples []float64
+
+// results maps a benchmark name to the samples of each of its metrics.
+type results map[string]map[string]samples
+
+// parseResults returns the results read from r along with the configuration
+// lines of the run, such as goos, cpu-model or gomaxprocs.
+func parseResults(r io.Reader) (results, map[string]string, error) {
+	set, err := benchfmt.Parse(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make(results)
+	for _, result := range set.Results {
+		for _, unit := range _gatedUnits {
+			value, ok := result.Value(unit)
+			if !ok {
+				continue
+			}
+			if res[result.Name] == nil {
+				res[result.Name] = make(map[string]samples)
+			}
+			res[result.Name][unit] = append(res[result.Name][unit], value)
+		}
+	}
+	return res, set.Config, nil
+}
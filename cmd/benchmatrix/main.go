@@ -0,0 +1,203 @@
+// Command benchmatrix runs the benchmarks against several logy releases side
+// by side and prints a table per metric comparing them to the first one:
+//
+//	go run ./cmd/benchmatrix -bench WithContext v0.1.0 v0.2.0 ../logy
+//
+// Versions are module versions, or paths to a local logy checkout. Each is run
+// with a copy of go.mod requiring or replacing logy, passed to go test with
+// -modfile, so the go.mod of the repository is left alone.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+const logyModule = "github.com/procyon-projects/logy"
+
+type config struct {
+	bench  string
+	count  int
+	filter *regexp.Regexp
+	units  []string
+}
+
+func main() {
+	var cfg config
+	var filter, units string
+	flag.StringVar(&cfg.bench, "bench", ".", "run the benchmarks matching `regexp`")
+	flag.IntVar(&cfg.count, "count", 5, "runs of each benchmark per version")
+	flag.StringVar(&filter, "filter", "/Logy", "tabulate the benchmarks matching `regexp`")
+	flag.StringVar(&units, "units", "ns/op,B/op,allocs/op", "comma separated metrics to tabulate")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: benchmatrix [flags] version|dir...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	if cfg.filter, err = regexp.Compile(filter); err != nil {
+		fatal(err)
+	}
+	cfg.units = strings.Split(units, ",")
+
+	var versions []string
+	sets := make(map[string]*benchfmt.Set)
+	for _, version := range flag.Args() {
+		set, err := runVersion(cfg, version)
+		if err != nil {
+			// A release with an incompatible API doesn't stop the others.
+			fmt.Fprintf(os.Stderr, "benchmatrix: %s: %v\n", version, err)
+			continue
+		}
+		versions = append(versions, version)
+		sets[version] = set
+	}
+	if len(versions) == 0 {
+		os.Exit(1)
+	}
+
+	for _, unit := range cfg.units {
+		printTable(os.Stdout, unit, versions, medians(cfg, sets, unit))
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "benchmatrix:", err)
+	os.Exit(2)
+}
+
+// runVersion runs the benchmarks with logy at the given version or checkout.
+func runVersion(cfg config, version string) (*benchfmt.Set, error) {
+	dir, err := os.MkdirTemp("", "benchmatrix")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	modfile := filepath.Join(dir, "go.mod")
+	if err := copyFile("go.mod", modfile); err != nil {
+		return nil, err
+	}
+	// go.sum is optional, -mod=mod fills in what is missing.
+	if err := copyFile("go.sum", filepath.Join(dir, "go.sum")); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	edits := []string{"-dropreplace=" + logyModule, "-require=" + logyModule + "@" + version}
+	if isLocal(version) {
+		path, err := filepath.Abs(version)
+		if err != nil {
+			return nil, err
+		}
+		edits = []string{"-replace=" + logyModule + "=" + path}
+	}
+	if err := run(io.Discard, "go", append([]string{"mod", "edit", "-modfile=" + modfile}, edits...)...); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(os.Stderr, "benchmatrix: running %s\n", version)
+	err = run(io.MultiWriter(&out, os.Stderr), "go", "test", "-modfile="+modfile, "-mod=mod",
+		"-run", "XXX", "-bench", cfg.bench, "-benchmem", "-count", strconv.Itoa(cfg.count), ".")
+	if err != nil {
+		return nil, err
+	}
+	return benchfmt.Parse(&out)
+}
+
+func isLocal(version string) bool {
+	return strings.HasPrefix(version, ".") || filepath.IsAbs(version)
+}
+
+func run(stdout io.Writer, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout, cmd.Stderr = stdout, os.Stderr
+	return cmd.Run()
+}
+
+func copyFile(from, to string) error {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(to, data, 0o644)
+}
+
+// medians returns the median of unit for every benchmark matching the filter,
+// by benchmark and version.
+func medians(cfg config, sets map[string]*benchfmt.Set, unit string) map[string]map[string]float64 {
+	table := make(map[string]map[string]float64)
+	for version, set := range sets {
+		values := make(map[string][]float64)
+		for _, res := range set.Results {
+			if v, ok := res.Value(unit); ok && cfg.filter.MatchString(res.Name) {
+				values[res.Name] = append(values[res.Name], v)
+			}
+		}
+		for name, vs := range values {
+			if table[name] == nil {
+				table[name] = make(map[string]float64)
+			}
+			table[name][version] = median(vs)
+		}
+	}
+	return table
+}
+
+func median(vs []float64) float64 {
+	sorted := append([]float64(nil), vs...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// printTable prints a markdown table of one metric, with the change relative
+// to the first version next to the values of the others.
+func printTable(w io.Writer, unit string, versions []string, table map[string]map[string]float64) {
+	if len(table) == 0 {
+		return
+	}
+	names := make([]string, 0, len(table))
+	for name := range table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "**%s**\n\n| Benchmark | %s |\n|:--|%s\n", unit, strings.Join(versions, " | "), strings.Repeat("--:|", len(versions)))
+	for _, name := range names {
+		cells := make([]string, len(versions))
+		base, hasBase := table[name][versions[0]]
+		for i, version := range versions {
+			v, ok := table[name][version]
+			switch {
+			case !ok:
+				cells[i] = "-"
+			case i == 0 || !hasBase || base == 0:
+				cells[i] = strconv.FormatFloat(v, 'g', 4, 64)
+			default:
+				cells[i] = fmt.Sprintf("%s (%+.1f%%)", strconv.FormatFloat(v, 'g', 4, 64), (v-base)/base*100)
+			}
+		}
+		fmt.Fprintf(w, "| %s | %s |\n", name, strings.Join(cells, " | "))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintTable(t *testing.T) {
+	table := map[string]map[string]float64{
+		"BenchmarkWithContext/Logy":    {"v0.1.0": 80, "v0.2.0": 60},
+		"BenchmarkWithoutFields/Logy":  {"v0.1.0": 0, "v0.2.0": 1},
+		"BenchmarkWithContext/Logy.Of": {"v0.2.0": 12},
+	}
+
+	var buf bytes.Buffer
+	printTable(&buf, "ns/op", []string{"v0.1.0", "v0.2.0"}, table)
+	want := `**ns/op**
+
+| Benchmark | v0.1.0 | v0.2.0 |
+|:--|--:|--:|
+| BenchmarkWithContext/Logy | 80 | 60 (-25.0%) |
+| BenchmarkWithContext/Logy.Of | - | 12 |
+| BenchmarkWithoutFields/Logy | 0 | 1 |
+
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+// Command benchprof prints the top functions of the CPU profiles written by
+// the benchmarks with -benchprofile, merging the scenarios of each library:
+//
+//	go test -run XXX -bench WithContext -benchprofile profiles
+//	go run ./cmd/benchprof -top 15 profiles
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+const cpuProfileSuffix = ".cpu.pprof"
+
+func main() {
+	top := flag.Int("top", 10, "number of functions to print per library")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: benchprof [-top n] dir\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *top); err != nil {
+		fmt.Fprintln(os.Stderr, "benchprof:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, top int) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+cpuProfileSuffix))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no CPU profiles in %s", dir)
+	}
+
+	libraries := groupByLibrary(files)
+	names := make([]string, 0, len(libraries))
+	for name := range libraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("### %s (%d profiles)\n", name, len(libraries[name]))
+		args := append([]string{"tool", "pprof", "-top", "-nodecount=" + strconv.Itoa(top)}, libraries[name]...)
+		cmd := exec.Command("go", args...)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pprof %s: %w", name, err)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// groupByLibrary groups profile files by the library they were written for.
+// Profiles are named after their benchmark with slashes turned into dashes,
+// e.g. BenchmarkHooks-rs-zerolog-NoOp-5.cpu.pprof, so the library is looked
+// for among the parts of the sub-benchmark path. The profiles of benchmarks
+// naming no library are grouped by benchmark.
+func groupByLibrary(files []string) map[string][]string {
+	libraries := make(map[string][]string)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), cpuProfileSuffix)
+		library, ok := libraryOf(name)
+		if !ok {
+			library = strings.SplitN(name, "-", 2)[0]
+		}
+		libraries[library] = append(libraries[library], file)
+	}
+	return libraries
+}
+
+// libraryOf returns the library named in the sub-benchmark path of a profile.
+func libraryOf(name string) (string, bool) {
+	parts := strings.Split(name, "-")[1:]
+	for _, library := range benchfmt.Libraries {
+		want := strings.Split(strings.ReplaceAll(library, "/", "-"), "-")
+		for i := 0; i+len(want) <= len(parts); i++ {
+			if namesLibrary(parts[i:i+len(want)], want) {
+				return library, true
+			}
+		}
+	}
+	return "", false
+}
+
+// namesLibrary tells whether parts are the parts of a library name, the last
+// one possibly followed by a variant, or by what profileName turned a space or
+// the #01 of a repeated sub-benchmark name into, as in Logy_console.
+func namesLibrary(parts, want []string) bool {
+	last := len(want) - 1
+	for i := 0; i < last; i++ {
+		if parts[i] != want[i] {
+			return false
+		}
+	}
+	return parts[last] == want[last] ||
+		strings.HasPrefix(parts[last], want[last]+".") ||
+		strings.HasPrefix(parts[last], want[last]+"_")
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupByLibrary(t *testing.T) {
+	files := []string{
+		"profiles/BenchmarkWithContext-rs-zerolog.cpu.pprof",
+		"profiles/BenchmarkWithoutFields-rs-zerolog.Check.cpu.pprof",
+		"profiles/BenchmarkWithContext-rs-zerolog.Formatting_01.cpu.pprof",
+		"profiles/BenchmarkWithContext-Zap.cpu.pprof",
+		"profiles/BenchmarkWithContext-Zap.Check.cpu.pprof",
+		"profiles/BenchmarkWithContext-Zap_01.cpu.pprof",
+		"profiles/BenchmarkHooks-Zap-NoOp-5.cpu.pprof",
+		"profiles/BenchmarkWithContext-Logy_console.cpu.pprof",
+		"profiles/BenchmarkFieldTypes-Int64-Logy.cpu.pprof",
+		"profiles/BenchmarkWriteSyscalls-Pipe-Logy.cpu.pprof",
+		"profiles/BenchmarkWithContext-go-kit-kit-log.cpu.pprof",
+		"profiles/BenchmarkNetworkSink-TCP.SlowReader-apex-log.cpu.pprof",
+		"profiles/BenchmarkSlowWriter-Errors-inconshreveable-log15.Buffered.cpu.pprof",
+		"profiles/BenchmarkWithoutFields-stdlib.Printf.cpu.pprof",
+		"profiles/BenchmarkObtainLogger-logy.Get.cpu.pprof",
+	}
+	want := map[string][]string{
+		"rs/zerolog":            files[:3],
+		"Zap":                   files[3:7],
+		"Logy":                  files[7:10],
+		"go-kit/kit/log":        files[10:11],
+		"apex/log":              files[11:12],
+		"inconshreveable/log15": files[12:13],
+		"stdlib":                files[13:14],
+		"BenchmarkObtainLogger": files[14:],
+	}
+	if got := groupByLibrary(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
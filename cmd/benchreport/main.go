@@ -0,0 +1,161 @@
+// Command benchreport turns benchmark output into JSON or CSV carrying every
+// metric along with the environment the benchmarks ran in, so results from
+// different machines can be compared:
+//
+//	go test -run XXX -bench . -benchmem | tee bench.txt
+//	go run ./cmd/benchreport -format csv -o results.csv bench.txt
+//	go run ./cmd/benchreport -format svg -log bench.txt
+//
+// The svg format writes a bar chart per scenario and metric into the -o
+// directory, the current one by default, highlighting logy.
+//
+// The environment comes from the configuration lines of the output: goos,
+// goarch and cpu from go test, and go, gomaxprocs, cpu-model and the module
+// versions printed by the benchmarks themselves.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+func main() {
+	format := flag.String("format", "json", "output format, json, csv or svg")
+	output := flag.String("o", "", "write to `file` instead of stdout, or the charts into this directory")
+	units := flag.String("units", "ns/op,allocs/op", "comma separated metrics to chart")
+	logScale := flag.Bool("log", false, "chart on a logarithmic scale")
+	flag.Parse()
+
+	sets, err := readSets(flag.Args())
+	if err != nil {
+		fatal(err)
+	}
+
+	if *format == "svg" {
+		dir := *output
+		if dir == "" {
+			dir = "."
+		}
+		if err := writeCharts(dir, sets, strings.Split(*units, ","), *logScale); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if err := writeReport(*output, *format, sets); err != nil {
+		fatal(err)
+	}
+}
+
+// writeReport writes the sets in the json or csv format into the file at
+// path, or to stdout when path is empty.
+func writeReport(path, format string, sets []*benchfmt.Set) error {
+	var write func(io.Writer, []*benchfmt.Set) error
+	switch format {
+	case "json":
+		write = writeJSON
+	case "csv":
+		write = writeCSV
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if path == "" {
+		return write(os.Stdout, sets)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f, sets)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "benchreport:", err)
+	os.Exit(1)
+}
+
+// readSets parses every file, or stdin when there are none.
+func readSets(files []string) ([]*benchfmt.Set, error) {
+	if len(files) == 0 {
+		set, err := benchfmt.Parse(os.Stdin)
+		return []*benchfmt.Set{set}, err
+	}
+
+	sets := make([]*benchfmt.Set, 0, len(files))
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		set, err := benchfmt.Parse(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+func writeJSON(w io.Writer, sets []*benchfmt.Set) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(sets)
+}
+
+// writeCSV writes a row per metric, followed by the environment of the run.
+func writeCSV(w io.Writer, sets []*benchfmt.Set) error {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, set := range sets {
+		for key := range set.Config {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	cw := csv.NewWriter(w)
+	header := append([]string{"name", "procs", "iterations", "unit", "value"}, keys...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, set := range sets {
+		for _, res := range set.Results {
+			for _, m := range res.Metrics {
+				row := []string{
+					res.Name,
+					strconv.Itoa(res.Procs),
+					strconv.Itoa(res.Iterations),
+					m.Unit,
+					strconv.FormatFloat(m.Value, 'g', -1, 64),
+				}
+				for _, key := range keys {
+					row = append(row, set.Config[key])
+				}
+				if err := cw.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+func TestWriteCSV(t *testing.T) {
+	sets := []*benchfmt.Set{
+		{
+			Config: map[string]string{"goos": "linux", "gomaxprocs": "8"},
+			Results: []benchfmt.Result{
+				{Name: "BenchmarkWithContext/Logy", Procs: 8, Iterations: 1000000, Metrics: []benchfmt.Metric{{Value: 85.29, Unit: "ns/op"}, {Value: 0, Unit: "allocs/op"}}},
+			},
+		},
+		{
+			Config: map[string]string{"goos": "darwin", "cpu-model": "Apple M1"},
+			Results: []benchfmt.Result{
+				{Name: "BenchmarkWithContext/Logy", Procs: 8, Iterations: 2000000, Metrics: []benchfmt.Metric{{Value: 61.5, Unit: "ns/op"}}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, sets); err != nil {
+		t.Fatal(err)
+	}
+	want := `name,procs,iterations,unit,value,cpu-model,gomaxprocs,goos
+BenchmarkWithContext/Logy,8,1000000,ns/op,85.29,,8,linux
+BenchmarkWithContext/Logy,8,1000000,allocs/op,0,,8,linux
+BenchmarkWithContext/Logy,8,2000000,ns/op,61.5,Apple M1,,darwin
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
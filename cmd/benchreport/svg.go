@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+const (
+	chartWidth   = 760
+	chartLabels  = 220 // width of the library names on the left
+	chartValues  = 110 // room for the value printed after a bar
+	chartTop     = 40
+	barHeight    = 20
+	barGap       = 6
+	logyColor    = "#e4572e"
+	libraryColor = "#8d99ae"
+)
+
+// bar is one library of a chart.
+type bar struct {
+	library string
+	value   float64
+}
+
+// chart is a bar chart of one metric of one scenario.
+type chart struct {
+	scenario string
+	unit     string
+	logScale bool
+	bars     []bar
+}
+
+// charts builds a chart per scenario and unit, with a bar per library. A
+// library run several times is charted by its median.
+func charts(sets []*benchfmt.Set, units []string, logScale bool) []chart {
+	type key struct{ scenario, unit string }
+	values := make(map[key]map[string][]float64)
+	var keys []key
+	for _, set := range sets {
+		for _, res := range set.Results {
+			scenario, library, ok := splitLibrary(res.Name)
+			if !ok {
+				continue
+			}
+			for _, unit := range units {
+				v, ok := res.Value(unit)
+				if !ok {
+					continue
+				}
+				k := key{scenario, unit}
+				if values[k] == nil {
+					values[k] = make(map[string][]float64)
+					keys = append(keys, k)
+				}
+				values[k][library] = append(values[k][library], v)
+			}
+		}
+	}
+
+	charts := make([]chart, 0, len(keys))
+	for _, k := range keys {
+		c := chart{scenario: k.scenario, unit: k.unit, logScale: logScale}
+		for library, vs := range values[k] {
+			c.bars = append(c.bars, bar{library: library, value: median(vs)})
+		}
+		sort.Slice(c.bars, func(i, j int) bool {
+			if c.bars[i].value != c.bars[j].value {
+				return c.bars[i].value < c.bars[j].value
+			}
+			return c.bars[i].library < c.bars[j].library
+		})
+		charts = append(charts, c)
+	}
+	return charts
+}
+
+// splitLibrary splits a benchmark name into the library named last in its
+// path, with its variant, and the rest of the path, the scenario: both
+// BenchmarkHooks/Zap/NoOp/5 and BenchmarkNetworkSink/TCP/Zap chart Zap, in
+// BenchmarkHooks/NoOp/5 and in BenchmarkNetworkSink/TCP. Names naming no
+// library are charted by their last part, as BenchmarkObtainLogger/logy.Get.
+func splitLibrary(name string) (scenario, library string, ok bool) {
+	parts := strings.Split(name, "/")
+	for end := len(parts); end > 1; end-- {
+		for _, l := range benchfmt.Libraries {
+			want := strings.Split(l, "/")
+			start := end - len(want)
+			if start < 1 || !namesLibrary(parts[start:end], want) {
+				continue
+			}
+			rest := append(append([]string(nil), parts[:start]...), parts[end:]...)
+			return strings.Join(rest, "/"), strings.Join(parts[start:end], "/"), true
+		}
+	}
+	if len(parts) < 2 {
+		return "", "", false
+	}
+	last := len(parts) - 1
+	return strings.Join(parts[:last], "/"), parts[last], true
+}
+
+// namesLibrary tells whether parts are the parts of a library name, the last
+// one possibly followed by a variant, by the underscore go test turns a space
+// into, as in Logy_console, or by the #01 of a repeated sub-benchmark name.
+func namesLibrary(parts, want []string) bool {
+	last := len(want) - 1
+	for i := 0; i < last; i++ {
+		if parts[i] != want[i] {
+			return false
+		}
+	}
+	return parts[last] == want[last] ||
+		strings.HasPrefix(parts[last], want[last]+".") ||
+		strings.HasPrefix(parts[last], want[last]+"_") ||
+		strings.HasPrefix(parts[last], want[last]+"#")
+}
+
+func median(vs []float64) float64 {
+	sorted := append([]float64(nil), vs...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// fileName returns the name of the chart file, e.g.
+// BenchmarkWithContext.ns-op.svg or BenchmarkHooks-NoOp-5.ns-op.svg.
+func (c chart) fileName() string {
+	return strings.ReplaceAll(c.scenario, "/", "-") + "." + strings.ReplaceAll(c.unit, "/", "-") + ".svg"
+}
+
+// scale maps a value to its share of the longest bar. The log scale is
+// log10(1+v), so that libraries not allocating at all still get a zero bar.
+func (c chart) scale(v float64) float64 {
+	max := 0.0
+	for _, b := range c.bars {
+		max = math.Max(max, b.value)
+	}
+	if max <= 0 {
+		return 0
+	}
+	if c.logScale {
+		return math.Log10(1+v) / math.Log10(1+max)
+	}
+	return v / max
+}
+
+func (c chart) writeSVG(w io.Writer) error {
+	height := chartTop + len(c.bars)*(barHeight+barGap) + barGap
+	plot := float64(chartWidth - chartLabels - chartValues)
+
+	title := c.scenario + " (" + c.unit
+	if c.logScale {
+		title += ", log scale"
+	}
+	title += ", lower is better)"
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="13">`+"\n",
+		chartWidth, height, chartWidth, height)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	fmt.Fprintf(&buf, `<text x="%d" y="24" font-size="15" font-weight="bold">%s</text>`+"\n", barGap, escape(title))
+	for i, b := range c.bars {
+		y := chartTop + i*(barHeight+barGap)
+		color, weight := libraryColor, "normal"
+		if strings.HasPrefix(b.library, "Logy") {
+			color, weight = logyColor, "bold"
+		}
+		width := c.scale(b.value) * plot
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="end" font-weight="%s">%s</text>`+"\n",
+			chartLabels-barGap, y+barHeight-5, weight, escape(b.library))
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+			chartLabels, y, width, barHeight, color)
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%d">%s</text>`+"\n",
+			float64(chartLabels)+width+float64(barGap), y+barHeight-5, escape(strconv.FormatFloat(b.value, 'g', 4, 64)+" "+c.unit))
+	}
+	buf.WriteString("</svg>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// writeCharts writes a chart per scenario and unit into dir.
+func writeCharts(dir string, sets []*benchfmt.Set, units []string, logScale bool) error {
+	for _, c := range charts(sets, units, logScale) {
+		f, err := os.Create(filepath.Join(dir, c.fileName()))
+		if err != nil {
+			return err
+		}
+		err = c.writeSVG(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/procyon-projects/logy/benchmarks/internal/benchfmt"
+)
+
+func TestCharts(t *testing.T) {
+	sets := []*benchfmt.Set{{Results: []benchfmt.Result{
+		{Name: "BenchmarkWithContext/Logy", Metrics: []benchfmt.Metric{{Value: 85, Unit: "ns/op"}, {Value: 0, Unit: "allocs/op"}}},
+		{Name: "BenchmarkWithContext/Logy", Metrics: []benchfmt.Metric{{Value: 87, Unit: "ns/op"}, {Value: 0, Unit: "allocs/op"}}},
+		{Name: "BenchmarkWithContext/sirupsen/logrus", Metrics: []benchfmt.Metric{{Value: 16246, Unit: "ns/op"}, {Value: 68, Unit: "allocs/op"}}},
+		{Name: "BenchmarkObtainLogger", Metrics: []benchfmt.Metric{{Value: 5, Unit: "ns/op"}}},
+	}}}
+
+	charts := charts(sets, []string{"ns/op", "allocs/op"}, true)
+	if len(charts) != 2 {
+		t.Fatalf("got %d charts, want 2", len(charts))
+	}
+	c := charts[0]
+	if c.fileName() != "BenchmarkWithContext.ns-op.svg" {
+		t.Errorf("got file name %q", c.fileName())
+	}
+	if len(c.bars) != 2 || c.bars[0] != (bar{"Logy", 86}) {
+		t.Errorf("got bars %v, want the median of Logy first", c.bars)
+	}
+	if c.scale(c.bars[1].value) != 1 {
+		t.Errorf("got scale %v for the longest bar, want 1", c.scale(c.bars[1].value))
+	}
+
+	var buf bytes.Buffer
+	if err := c.writeSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dec := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, buf.String())
+		}
+	}
+	if !strings.Contains(buf.String(), logyColor) {
+		t.Error("logy isn't highlighted")
+	}
+}
+
+func TestSplitLibrary(t *testing.T) {
+	for _, tt := range []struct {
+		name, scenario, library string
+	}{
+		{"BenchmarkWithContext/Logy", "BenchmarkWithContext", "Logy"},
+		{"BenchmarkWithContext/Logy_console", "BenchmarkWithContext", "Logy_console"},
+		{"BenchmarkWithContext/Zap#01", "BenchmarkWithContext", "Zap#01"},
+		{"BenchmarkWithoutFields/Zap.Check", "BenchmarkWithoutFields", "Zap.Check"},
+		{"BenchmarkWithoutFields/stdlib.Printf", "BenchmarkWithoutFields", "stdlib.Printf"},
+		{"BenchmarkHooks/Zap/NoOp/5", "BenchmarkHooks/NoOp/5", "Zap"},
+		{"BenchmarkHooks/go-kit/kit/log/NoOp/5", "BenchmarkHooks/NoOp/5", "go-kit/kit/log"},
+		{"BenchmarkNetworkSink/TCP/Zap", "BenchmarkNetworkSink/TCP", "Zap"},
+		{"BenchmarkNetworkSink/TCP.SlowReader/apex/log", "BenchmarkNetworkSink/TCP.SlowReader", "apex/log"},
+		{"BenchmarkMarshalers/Fallback/Struct/inconshreveable/log15", "BenchmarkMarshalers/Fallback/Struct", "inconshreveable/log15"},
+		{"BenchmarkObtainLogger/logy.Get", "BenchmarkObtainLogger", "logy.Get"},
+	} {
+		scenario, library, ok := splitLibrary(tt.name)
+		if !ok || scenario != tt.scenario || library != tt.library {
+			t.Errorf("splitLibrary(%q) = %q, %q, %v, want %q, %q", tt.name, scenario, library, ok, tt.scenario, tt.library)
+		}
+	}
+	if _, _, ok := splitLibrary("BenchmarkObtainLogger"); ok {
+		t.Error("split a benchmark without sub-benchmarks")
+	}
+}
@@ -0,0 +1,63 @@
+package benchmarks
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if bench := flag.Lookup("test.bench"); bench != nil && bench.Value.String() != "" {
+		printEnv(os.Stdout)
+	}
+	os.Exit(m.Run())
+}
+
+// printEnv writes what results depend on besides the code as configuration
+// lines of the benchmark format, next to the goos, goarch and cpu lines of go
+// test, so cmd/benchreport can attach them to the results.
+func printEnv(w io.Writer) {
+	fmt.Fprintf(w, "go: %s\n", runtime.Version())
+	fmt.Fprintf(w, "gomaxprocs: %d\n", runtime.GOMAXPROCS(0))
+	if model := cpuModel(); model != "" {
+		fmt.Fprintf(w, "cpu-model: %s\n", model)
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, dep := range info.Deps {
+		version := dep.Version
+		if dep.Replace != nil {
+			version += " => " + strings.TrimSpace(dep.Replace.Path+" "+dep.Replace.Version)
+		}
+		fmt.Fprintf(w, "module/%s: %s\n", dep.Path, version)
+	}
+}
+
+// cpuModel returns the model name from /proc/cpuinfo, empty where there is no
+// such file.
+func cpuModel() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,297 @@
+package benchmarks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	stdlog "log"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+)
+
+var (
+	errWrite = errors.New("disk is full")
+	errSync  = errors.New("sync failed")
+)
+
+type errorPathLogger struct {
+	name string
+	// reportsTo names where failed writes are reported, empty if they are
+	// silently dropped.
+	reportsTo string
+	// syncs tells whether shutting the logger down syncs its writer.
+	syncs bool
+	// ignoresWriter tells whether the logger writes somewhere failing the
+	// same way instead of into w, which it then can't sync.
+	ignoresWriter bool
+	// new returns a logger writing into w and reporting errors to errs, as a
+	// function logging one record and a function shutting the logger down.
+	new func(tb testing.TB, w io.Writer, errs io.Writer) (log func() error, shutdown func() error)
+}
+
+func errorPathLoggers() []errorPathLogger {
+	return []errorPathLogger{
+		{
+			// logy drops failed writes and has no Sync or Flush to call on
+			// shutdown.
+			name:          "Logy",
+			ignoresWriter: true,
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				// logy can't write into w, only to stdout, so loadLogyInto
+				// points it at a file failing every write the way w does:
+				// /dev/full, a full disk. Whatever it reports to stderr ends
+				// up in errs.
+				full, err := os.OpenFile("/dev/full", os.O_WRONLY, 0)
+				if err != nil {
+					tb.Skipf("no file failing every write for logy: %v", err)
+				}
+				detach := loadLogyInto(full, &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: newLogyJsonConsole()})
+				logger := logy.Get()
+				log := func() error {
+					logger.Info(getMessage(0))
+					return nil
+				}
+				return log, func() error {
+					detach()
+					return full.Close()
+				}
+			},
+		},
+		{
+			name: "exp/slog",
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				logger := slog.New(slog.NewJSONHandler(w))
+				return func() error {
+					logger.Info(getMessage(0))
+					return nil
+				}, func() error { return nil }
+			},
+		},
+		{
+			name:      "Zap",
+			reportsTo: "ErrorOutput",
+			syncs:     true,
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				logger := newZapLoggerTo(w, zap.DebugLevel).WithOptions(zap.ErrorOutput(zapcore.AddSync(errs)))
+				return func() error {
+					logger.Info(getMessage(0))
+					return nil
+				}, logger.Sync
+			},
+		},
+		{
+			name:      "rs/zerolog",
+			reportsTo: "ErrorHandler",
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				handler := zerolog.ErrorHandler
+				zerolog.ErrorHandler = func(err error) {
+					_, _ = io.WriteString(errs, err.Error())
+				}
+				logger := newZerologTo(w)
+				log := func() error {
+					logger.Info().Msg(getMessage(0))
+					return nil
+				}
+				return log, func() error {
+					zerolog.ErrorHandler = handler
+					return nil
+				}
+			},
+		},
+		{
+			name:      "apex/log",
+			reportsTo: "log",
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				logger := newApexLogTo(w)
+				return func() error {
+					logger.Info(getMessage(0))
+					return nil
+				}, func() error { return nil }
+			},
+		},
+		{
+			name:      "go-kit/kit/log",
+			reportsTo: "Log return value",
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				logger := newKitLogTo(w)
+				return func() error {
+					return logger.Log("msg", getMessage(0))
+				}, func() error { return nil }
+			},
+		},
+		{
+			name: "inconshreveable/log15",
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				logger := newLog15To(w)
+				return func() error {
+					logger.Info(getMessage(0))
+					return nil
+				}, func() error { return nil }
+			},
+		},
+		{
+			name:      "sirupsen/logrus",
+			reportsTo: "stderr",
+			new: func(tb testing.TB, w io.Writer, errs io.Writer) (func() error, func() error) {
+				logger := newLogrusTo(w)
+				return func() error {
+					logger.Info(getMessage(0))
+					return nil
+				}, func() error { return nil }
+			},
+		},
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// captureStderr copies everything written to os.Stderr and the standard logger
+// into w until the returned function is called.
+func captureStderr(tb testing.TB, w io.Writer) func() {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(w, r)
+	}()
+
+	stderr, output := os.Stderr, stdlog.Writer()
+	os.Stderr = pw
+	stdlog.SetOutput(pw)
+	return func() {
+		os.Stderr = stderr
+		stdlog.SetOutput(output)
+		_ = pw.Close()
+		<-done
+		_ = r.Close()
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	for _, l := range errorPathLoggers() {
+		l := l
+		t.Run(l.name, func(t *testing.T) {
+			errs := &lockedBuffer{}
+			w := &Discarder{}
+			w.SetError(errWrite)
+
+			log, shutdown := l.new(t, w, errs)
+			restore := captureStderr(t, errs)
+			err := log()
+			_ = shutdown()
+			restore()
+
+			reported := errors.Is(err, errWrite) || errors.Is(err, syscall.ENOSPC) ||
+				strings.Contains(errs.String(), errWrite.Error()) || strings.Contains(errs.String(), syscall.ENOSPC.Error())
+			switch {
+			case reported && l.reportsTo == "":
+				t.Errorf("failed write was reported, expected it to be dropped: %q", errs.String())
+			case !reported && l.reportsTo != "":
+				t.Errorf("failed write was not reported to %s", l.reportsTo)
+			}
+		})
+	}
+}
+
+func TestSyncOnShutdown(t *testing.T) {
+	for _, l := range errorPathLoggers() {
+		l := l
+		t.Run(l.name, func(t *testing.T) {
+			if l.ignoresWriter {
+				t.Skipf("%s doesn't write into the writer, there is no Sync to check", l.name)
+			}
+			// The writes fail too, which some libraries report to stderr.
+			defer silenceStderr(t)()
+			w := &Discarder{}
+			w.SetError(errSync)
+
+			log, shutdown := l.new(t, w, io.Discard)
+			_ = log()
+			err := shutdown()
+
+			if w.Called() != l.syncs {
+				t.Errorf("writer synced on shutdown: %v, want %v", w.Called(), l.syncs)
+			}
+			if got := errors.Is(err, errSync); got != l.syncs {
+				t.Errorf("sync error returned on shutdown: %v, want %v", got, l.syncs)
+			}
+		})
+	}
+}
+
+// TestLogyAfterWriteErrors checks failed writes neither block logy nor keep it
+// from writing once its target works again.
+func TestLogyAfterWriteErrors(t *testing.T) {
+	const records = 100
+	for _, l := range errorPathLoggers() {
+		if l.name != "Logy" {
+			continue
+		}
+		w := &Discarder{}
+		w.SetError(errWrite)
+		log, shutdown := l.new(t, w, io.Discard)
+		for i := 0; i < records; i++ {
+			_ = log()
+		}
+		_ = shutdown()
+	}
+
+	var out bytes.Buffer
+	restore := redirectLogy(&out, &logy.Config{Level: logy.LevelDebug, Console: newLogyJsonConsole()})
+	logy.Get().Info(getMessage(1))
+	restore()
+	if !strings.Contains(out.String(), getMessage(1)) {
+		t.Errorf("logy wrote %q after failed writes, want the record", out.String())
+	}
+}
+
+func BenchmarkWriteErrors(b *testing.B) {
+	b.Logf("Logging into a writer failing every write.")
+	for _, l := range errorPathLoggers() {
+		l := l
+		b.Run(l.name, func(b *testing.B) {
+			defer reportScenario(b, nil)()
+			defer silenceStderr(b)()
+			w := &Discarder{}
+			w.SetError(errWrite)
+			log, shutdown := l.new(b, w, io.Discard)
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = log()
+				}
+			})
+			b.StopTimer()
+			_ = shutdown()
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package benchmarks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+)
+
+// colour is a fmt.Stringer without any other marshaler.
+type colour int
+
+func (c colour) String() string {
+	switch c {
+	case 0:
+		return "red"
+	case 1:
+		return "green"
+	}
+	return "blue"
+}
+
+// point has no marshaler at all, libraries fall back to reflection for it.
+type point struct {
+	X, Y int
+	Tags []string
+}
+
+// node is an object nested in its parent, marshaled for logy, zap and zerolog.
+type node struct {
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+	Child *node  `json:"child,omitempty"`
+}
+
+func (n *node) MarshalObject(encoder logy.ObjectEncoder) error {
+	encoder.AddString("name", n.Name)
+	encoder.AddInt("depth", n.Depth)
+	if n.Child != nil {
+		return encoder.AddObject("child", n.Child)
+	}
+	return nil
+}
+
+func (n *node) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", n.Name)
+	enc.AddInt("depth", n.Depth)
+	if n.Child != nil {
+		return enc.AddObject("child", n.Child)
+	}
+	return nil
+}
+
+func (n *node) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("name", n.Name).Int("depth", n.Depth)
+	if n.Child != nil {
+		e.Object("child", n.Child)
+	}
+}
+
+func (n *node) slogAttr(key string) slog.Attr {
+	attrs := []slog.Attr{slog.String("name", n.Name), slog.Int("depth", n.Depth)}
+	if n.Child != nil {
+		attrs = append(attrs, n.Child.slogAttr("child"))
+	}
+	return slog.Group(key, attrs...)
+}
+
+var (
+	_bytes   = []byte("binary\x00payload\xff")
+	_rawJSON = json.RawMessage(`{"id":42,"tags":["a","b"]}`)
+	_addr    = netip.MustParseAddr("192.0.2.1")
+	_point   = &point{X: 1, Y: 2, Tags: []string{"a", "b"}}
+	_tree    = &node{Name: "root", Depth: 0, Child: &node{Name: "branch", Depth: 1, Child: &node{Name: "leaf", Depth: 2}}}
+	_fields  = map[string]int{"a": 1, "b": 2, "c": 3}
+)
+
+// fieldType is one type of field, given as a plain value to the libraries
+// taking key-value pairs and with their typed API to the others.
+type fieldType struct {
+	name    string
+	value   interface{}
+	zap     zap.Field
+	zerolog func(e *zerolog.Event) *zerolog.Event
+	slog    slog.Attr
+}
+
+var _fieldTypes = []fieldType{
+	{"Bool", true, zap.Bool("field", true),
+		func(e *zerolog.Event) *zerolog.Event { return e.Bool("field", true) },
+		slog.Bool("field", true)},
+	{"Float64", 3.14159, zap.Float64("field", 3.14159),
+		func(e *zerolog.Event) *zerolog.Event { return e.Float64("field", 3.14159) },
+		slog.Float64("field", 3.14159)},
+	{"Int64", int64(-1 << 40), zap.Int64("field", -1<<40),
+		func(e *zerolog.Event) *zerolog.Event { return e.Int64("field", -1<<40) },
+		slog.Int64("field", -1<<40)},
+	{"Uint64", uint64(1 << 63), zap.Uint64("field", 1<<63),
+		func(e *zerolog.Event) *zerolog.Event { return e.Uint64("field", 1<<63) },
+		slog.Uint64("field", 1<<63)},
+	{"Duration", 1500 * time.Millisecond, zap.Duration("field", 1500*time.Millisecond),
+		func(e *zerolog.Event) *zerolog.Event { return e.Dur("field", 1500*time.Millisecond) },
+		slog.Duration("field", 1500*time.Millisecond)},
+	{"Binary", _bytes, zap.Binary("field", _bytes),
+		func(e *zerolog.Event) *zerolog.Event { return e.Bytes("field", _bytes) },
+		slog.Any("field", _bytes)},
+	{"Nil", nil, zap.Reflect("field", nil),
+		func(e *zerolog.Event) *zerolog.Event { return e.Interface("field", nil) },
+		slog.Any("field", nil)},
+	{"Map", _fields, zap.Any("field", _fields),
+		func(e *zerolog.Event) *zerolog.Event { return e.Interface("field", _fields) },
+		slog.Any("field", _fields)},
+	{"Pointer", _point, zap.Reflect("field", _point),
+		func(e *zerolog.Event) *zerolog.Event { return e.Interface("field", _point) },
+		slog.Any("field", _point)},
+	{"RawJSON", _rawJSON, zap.Reflect("field", _rawJSON),
+		func(e *zerolog.Event) *zerolog.Event { return e.RawJSON("field", _rawJSON) },
+		slog.Any("field", _rawJSON)},
+	{"Stringer", colour(1), zap.Stringer("field", colour(1)),
+		func(e *zerolog.Event) *zerolog.Event { return e.Stringer("field", colour(1)) },
+		slog.Any("field", colour(1))},
+	{"TextMarshaler", _addr, zap.Any("field", _addr),
+		func(e *zerolog.Event) *zerolog.Event { return e.Interface("field", _addr) },
+		slog.Any("field", _addr)},
+	{"Nested", _tree, zap.Object("field", _tree),
+		func(e *zerolog.Event) *zerolog.Event { return e.Object("field", _tree) },
+		_tree.slogAttr("field")},
+	{"Any", *_point, zap.Any("field", *_point),
+		func(e *zerolog.Event) *zerolog.Event { return e.Interface("field", *_point) },
+		slog.Any("field", *_point)},
+}
+
+// fieldLoggers returns for each library a function logging a record with the
+// field of the given type into w.
+func fieldLoggers(w io.Writer, ft fieldType) []struct {
+	name string
+	log  func()
+} {
+	ctx := logy.WithValue(logy.WithContextFields(context.Background()), "field", ft.value)
+	l := newLibraryLoggers(w)
+
+	return []struct {
+		name string
+		log  func()
+	}{
+		{"Logy", func() { l.logy.I(ctx, getMessage(0)) }},
+		{"exp/slog", func() { l.slog.LogAttrs(slog.LevelInfo, getMessage(0), ft.slog) }},
+		{"Zap", func() { l.zap.Info(getMessage(0), ft.zap) }},
+		{"rs/zerolog", func() { ft.zerolog(l.zerolog.Info()).Msg(getMessage(0)) }},
+		{"apex/log", func() { l.apex.WithField("field", ft.value).Info(getMessage(0)) }},
+		{"go-kit/kit/log", func() { _ = l.kit.Log("msg", getMessage(0), "field", ft.value) }},
+		{"inconshreveable/log15", func() { l.log15.Info(getMessage(0), "field", ft.value) }},
+		{"sirupsen/logrus", func() { l.logrus.WithFields(logrus.Fields{"field": ft.value}).Info(getMessage(0)) }},
+	}
+}
+
+func BenchmarkFieldTypes(b *testing.B) {
+	b.Logf("Logging a single field of each type, with the typed API of each library.")
+	d := &Discarder{}
+	defer loadLogyIntoDevNull(b, &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: newLogyJsonConsole()})()
+
+	for _, ft := range _fieldTypes {
+		for _, l := range fieldLoggers(d, ft) {
+			l := l
+			b.Run(ft.name+"/"+l.name, func(b *testing.B) {
+				defer reportScenario(b, d)()
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						l.log()
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package benchmarks
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+var _heapProfiles = flag.String("heapprofiles", "", "write the heap and allocs profiles of every sub-benchmark into `dir`")
+
+const (
+	gcAllocBytes = iota
+	gcAllocObjects
+	gcHeapGoal
+	gcHeapObjects
+)
+
+var _gcMetrics = []string{
+	gcAllocBytes:   "/gc/heap/allocs:bytes",
+	gcAllocObjects: "/gc/heap/allocs:objects",
+	gcHeapGoal:     "/gc/heap/goal:bytes",
+	gcHeapObjects:  "/memory/classes/heap/objects:bytes",
+}
+
+// gcStats is a snapshot of the collector's counters.
+type gcStats struct {
+	numGC        uint32
+	pauseTotalNs uint64
+	samples      []metrics.Sample
+}
+
+func readGCStats() gcStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	s := gcStats{numGC: m.NumGC, pauseTotalNs: m.PauseTotalNs, samples: make([]metrics.Sample, len(_gcMetrics))}
+	for i, name := range _gcMetrics {
+		s.samples[i].Name = name
+	}
+	metrics.Read(s.samples)
+	return s
+}
+
+func (s gcStats) metric(i int) float64 {
+	if s.samples[i].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return float64(s.samples[i].Value.Uint64())
+}
+
+// heapPeak returns the heap high-water of a run from the snapshot taken after
+// it, so nothing samples the heap while it is timed. The heap only grows
+// between collections: without one it peaked at its final size, otherwise at
+// about the goal the collector set for it.
+func heapPeak(before, after gcStats) float64 {
+	peak := after.metric(gcHeapObjects)
+	if after.numGC != before.numGC && after.metric(gcHeapGoal) > peak {
+		peak = after.metric(gcHeapGoal)
+	}
+	return peak
+}
+
+// reportGC reports the collections, pause time and heap high-water of the
+// benchmark from the call until the returned function runs, and writes its
+// heap profiles when -heapprofiles is set.
+func reportGC(b *testing.B) func() {
+	if *_heapProfiles != "" {
+		writeProfile(b, *_heapProfiles, "allocs", "allocs.base")
+	}
+	before := readGCStats()
+	return func() {
+		b.StopTimer()
+		after := readGCStats()
+
+		n := float64(b.N)
+		b.ReportMetric(float64(after.numGC-before.numGC)/n, "gcs/op")
+		b.ReportMetric(float64(after.pauseTotalNs-before.pauseTotalNs)/n, "gc-pause-ns/op")
+		b.ReportMetric(heapPeak(before, after), "heap-peak-B")
+		b.ReportMetric((after.metric(gcAllocBytes)-before.metric(gcAllocBytes))/n, "gc-alloc-B/op")
+		b.ReportMetric((after.metric(gcAllocObjects)-before.metric(gcAllocObjects))/n, "gc-alloc-objs/op")
+
+		if *_heapProfiles != "" {
+			runtime.GC()
+			writeProfile(b, *_heapProfiles, "heap", "heap")
+			writeProfile(b, *_heapProfiles, "allocs", "allocs")
+		}
+	}
+}
+
+// profileName turns a benchmark name into a file name, e.g.
+// BenchmarkWithContext/rs/zerolog becomes BenchmarkWithContext-rs-zerolog.
+func profileName(b *testing.B) string {
+	return strings.NewReplacer("/", "-", " ", "_", "#", "_").Replace(b.Name())
+}
+
+// writeProfile writes the named profile into dir, the file named after the
+// benchmark and suffix. The allocs profile covers the whole run so far, which
+// is why it is also written before the benchmark as the allocs.base one: diff
+// them with pprof -base to get the sub-benchmark alone.
+func writeProfile(b *testing.B, dir, profile, suffix string) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		b.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dir, profileName(b)+"."+suffix+".pprof"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	err = pprof.Lookup(profile).WriteTo(f, 0)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,153 @@
+package benchmarks
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/procyon-projects/logy"
+)
+
+// _categorySegments make up a hierarchy of package paths, each category the
+// parent of the next one.
+var _categorySegments = []string{
+	"github.com", "procyon-projects", "logy", "test", "benchmark",
+	"http", "server", "handler", "middleware", "auth",
+}
+
+// category returns the package path of the category at depth, the root
+// category being at depth 1.
+func category(depth int) string {
+	return strings.Join(_categorySegments[:depth], "/")
+}
+
+// _categoryLevels are the levels configured for some of the categories, the
+// others inheriting theirs from the nearest configured parent.
+var _categoryLevels = map[int]logy.Level{
+	3: logy.LevelInfo,
+	5: logy.LevelDebug,
+	7: logy.LevelError,
+	9: logy.LevelTrace,
+}
+
+// hierarchyConfig returns a config with the category levels, the category at
+// parentDepth set to parentLevel instead.
+func hierarchyConfig(parentDepth int, parentLevel logy.Level) *logy.Config {
+	console := newLogyJsonConsole()
+	console.Target = logy.TargetDiscard
+	config := &logy.Config{Level: logy.LevelWarn, Console: console, Package: map[string]*logy.PackageConfig{}}
+	for depth, lvl := range _categoryLevels {
+		config.Package[category(depth)] = &logy.PackageConfig{Level: lvl, UseParentHandlers: true}
+	}
+	config.Package[category(parentDepth)] = &logy.PackageConfig{Level: parentLevel, UseParentHandlers: true}
+	return config
+}
+
+// inheritedLevel returns the level the category at depth is expected to get:
+// its own if configured, the nearest configured parent's otherwise.
+func inheritedLevel(config *logy.Config, depth int) logy.Level {
+	for ; depth > 0; depth-- {
+		if pc, ok := config.Package[category(depth)]; ok {
+			return pc.Level
+		}
+	}
+	return config.Level
+}
+
+func BenchmarkHierarchy(b *testing.B) {
+	b.Logf("Resolving and reconfiguring the levels of loggers in a hierarchy of %d categories.", len(_categorySegments))
+	defer loadLogyJsonDiscard()
+	_ = logy.LoadConfig(hierarchyConfig(5, logy.LevelDebug))
+
+	for _, depth := range []int{1, 3, 6, len(_categorySegments)} {
+		leaf := logy.Named(category(depth))
+		b.Run("IsLoggable/Depth"+strconv.Itoa(depth), func(b *testing.B) {
+			defer reportGC(b)()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					leaf.IsLoggable(logy.LevelDebug)
+				}
+			})
+		})
+	}
+
+	// The leaf is at trace level, its parent at error level.
+	leaf := logy.Named(category(len(_categorySegments)))
+	parent := logy.Named(category(7))
+	b.Run("Log/Enabled", func(b *testing.B) {
+		defer reportGC(b)()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				leaf.Info(getMessage(0))
+			}
+		})
+	})
+	b.Run("Log/Disabled", func(b *testing.B) {
+		defer reportGC(b)()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				parent.Info(getMessage(0))
+			}
+		})
+	})
+
+	// Reconfiguring has every registered logger resolve its level again, so
+	// it is measured once the given number of loggers are spread over the
+	// categories. The names repeat, so each run adds only the loggers the
+	// previous one lacked.
+	configs := []*logy.Config{hierarchyConfig(5, logy.LevelError), hierarchyConfig(5, logy.LevelDebug)}
+	for _, loggers := range []int{100, 1000, 10000} {
+		for i := 0; i < loggers; i++ {
+			logy.Named(category(1+i%len(_categorySegments)) + "/logger" + strconv.Itoa(i))
+		}
+		b.Run("Reconfigure/Loggers"+strconv.Itoa(loggers), func(b *testing.B) {
+			defer reportGC(b)()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = logy.LoadConfig(configs[i%len(configs)])
+			}
+		})
+	}
+}
+
+func TestHierarchyInheritance(t *testing.T) {
+	defer loadLogyJsonDiscard()
+
+	loggers := make([]*logy.Logger, len(_categorySegments)+1)
+	for depth := 1; depth < len(loggers); depth++ {
+		loggers[depth] = logy.Named(category(depth))
+	}
+	// The categories below the reconfigured one at depth 5 inherit its level
+	// until the one at depth 7 overrides it.
+	for _, parentLevel := range []logy.Level{logy.LevelDebug, logy.LevelError, logy.LevelOff} {
+		config := hierarchyConfig(5, parentLevel)
+		if err := logy.LoadConfig(config); err != nil {
+			t.Fatalf("loading the config: %v", err)
+		}
+		for depth := 1; depth < len(loggers); depth++ {
+			want := inheritedLevel(config, depth)
+			if got := loggers[depth].Level(); got != want {
+				t.Errorf("%s is at level %v with the parent at %v, want %v", category(depth), got, parentLevel, want)
+			}
+			if got := loggers[depth].IsLoggable(logy.LevelInfo); got != (logy.LevelInfo <= want) {
+				t.Errorf("%s: IsLoggable(info) is %v with the parent at %v", category(depth), got, parentLevel)
+			}
+		}
+	}
+
+	// A logger first got after the config is loaded resolves its level the
+	// same way.
+	config := hierarchyConfig(5, logy.LevelError)
+	_ = logy.LoadConfig(config)
+	late := logy.Named(category(6) + "/late")
+	if got, want := late.Level(), inheritedLevel(config, 6); got != want {
+		t.Errorf("%s is at level %v, want %v", category(6)+"/late", got, want)
+	}
+}
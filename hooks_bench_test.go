@@ -0,0 +1,442 @@
+package benchmarks
+
+import (
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	apex "github.com/apex/log"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+type hookKind int
+
+const (
+	noOpHook hookKind = iota
+	fieldHook
+	metricsHook
+)
+
+func (k hookKind) String() string {
+	switch k {
+	case noOpHook:
+		return "NoOp"
+	case fieldHook:
+		return "AddField"
+	default:
+		return "Metrics"
+	}
+}
+
+var (
+	_hookCounts = []int{0, 1, 5, 20}
+	_hookKeys   = fakeHookKeys(20)
+)
+
+func fakeHookKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "hook" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+// levelCounter is what a "metrics" hook does: count the records it sees by
+// level. Each library maps its own levels onto the counter slots.
+type levelCounter struct {
+	counts [8]int64
+}
+
+func (c *levelCounter) inc(slot int) {
+	atomic.AddInt64(&c.counts[slot&7], 1)
+}
+
+func (c *levelCounter) total() int64 {
+	var n int64
+	for i := range c.counts {
+		n += atomic.LoadInt64(&c.counts[i])
+	}
+	return n
+}
+
+// fieldCore is a zap core middleware adding a single field to every entry.
+type fieldCore struct {
+	zapcore.Core
+	field zap.Field
+}
+
+func (c fieldCore) With(fields []zapcore.Field) zapcore.Core {
+	return fieldCore{c.Core.With(fields), c.field}
+}
+
+func (c fieldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c fieldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, append(fields, c.field))
+}
+
+type logrusHook struct {
+	kind    hookKind
+	key     string
+	counter *levelCounter
+}
+
+func (h *logrusHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *logrusHook) Fire(e *logrus.Entry) error {
+	switch h.kind {
+	case fieldHook:
+		e.Data[h.key] = 1
+	case metricsHook:
+		h.counter.inc(int(e.Level))
+	}
+	return nil
+}
+
+// slogHookHandler is a slog handler middleware running hook on each record.
+type slogHookHandler struct {
+	slog.Handler
+	hook func(r *slog.Record)
+}
+
+func (h slogHookHandler) Handle(r slog.Record) error {
+	h.hook(&r)
+	return h.Handler.Handle(r)
+}
+
+func (h slogHookHandler) WithAttrs(as []slog.Attr) slog.Handler {
+	return slogHookHandler{h.Handler.WithAttrs(as), h.hook}
+}
+
+func (h slogHookHandler) WithGroup(name string) slog.Handler {
+	return slogHookHandler{h.Handler.WithGroup(name), h.hook}
+}
+
+// apexHookHandler is an apex/log handler middleware running hook on each entry.
+type apexHookHandler struct {
+	next apex.Handler
+	hook func(e *apex.Entry)
+}
+
+func (h apexHookHandler) HandleLog(e *apex.Entry) error {
+	h.hook(e)
+	return h.next.HandleLog(e)
+}
+
+type hookedLogger struct {
+	name string
+	// build returns a function logging a single record through n hooks of
+	// the given kind, or nil if the library has no way to install them.
+	build func(kind hookKind, n int, counter *levelCounter) func()
+}
+
+func hookedLoggers() []hookedLogger {
+	return []hookedLogger{
+		{
+			name: "Logy",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				// logy has no hook or handler middleware API, so only the
+				// baseline without hooks can be measured.
+				if kind == metricsHook || n > 0 {
+					return nil
+				}
+				logger := logy.Get()
+				_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard, Enabled: true, Json: &logy.JsonConfig{
+					Enabled: true,
+				}}})
+				return func() {
+					logger.Info(getMessage(0))
+				}
+			},
+		},
+		{
+			name: "exp/slog",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				var handler slog.Handler = slog.NewJSONHandler(io.Discard)
+				for i := 0; i < n; i++ {
+					key := _hookKeys[i]
+					switch kind {
+					case noOpHook:
+						handler = slogHookHandler{handler, func(*slog.Record) {}}
+					case fieldHook:
+						handler = slogHookHandler{handler, func(r *slog.Record) {
+							r.AddAttrs(slog.Int(key, 1))
+						}}
+					case metricsHook:
+						handler = slogHookHandler{handler, func(r *slog.Record) {
+							counter.inc(int(r.Level+4) / 4)
+						}}
+					}
+				}
+				logger := slog.New(handler)
+				return func() {
+					logger.Info(getMessage(0))
+				}
+			},
+		},
+		{
+			name: "Zap",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				logger := newZapLogger(zap.DebugLevel)
+				if n == 0 {
+					return func() {
+						logger.Info(getMessage(0))
+					}
+				}
+				var opts []zap.Option
+				switch kind {
+				case noOpHook:
+					hooks := make([]func(zapcore.Entry) error, n)
+					for i := range hooks {
+						hooks[i] = func(zapcore.Entry) error { return nil }
+					}
+					opts = append(opts, zap.Hooks(hooks...))
+				case fieldHook:
+					for i := 0; i < n; i++ {
+						field := zap.Int(_hookKeys[i], 1)
+						opts = append(opts, zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+							return fieldCore{c, field}
+						}))
+					}
+				case metricsHook:
+					hooks := make([]func(zapcore.Entry) error, n)
+					for i := range hooks {
+						hooks[i] = func(e zapcore.Entry) error {
+							counter.inc(int(e.Level) + 1)
+							return nil
+						}
+					}
+					opts = append(opts, zap.Hooks(hooks...))
+				}
+				logger = logger.WithOptions(opts...)
+				return func() {
+					logger.Info(getMessage(0))
+				}
+			},
+		},
+		{
+			name: "rs/zerolog",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				logger := newZerolog()
+				for i := 0; i < n; i++ {
+					key := _hookKeys[i]
+					switch kind {
+					case noOpHook:
+						logger = logger.Hook(zerolog.HookFunc(func(*zerolog.Event, zerolog.Level, string) {}))
+					case fieldHook:
+						logger = logger.Hook(zerolog.HookFunc(func(e *zerolog.Event, _ zerolog.Level, _ string) {
+							e.Int(key, 1)
+						}))
+					case metricsHook:
+						logger = logger.Hook(zerolog.HookFunc(func(_ *zerolog.Event, l zerolog.Level, _ string) {
+							counter.inc(int(l) + 1)
+						}))
+					}
+				}
+				return func() {
+					logger.Info().Msg(getMessage(0))
+				}
+			},
+		},
+		{
+			name: "apex/log",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				logger := newApexLog()
+				for i := 0; i < n; i++ {
+					key := _hookKeys[i]
+					switch kind {
+					case noOpHook:
+						logger.Handler = apexHookHandler{logger.Handler, func(*apex.Entry) {}}
+					case fieldHook:
+						// Entries get a fresh Fields map per call, so it's safe
+						// to add to it in place.
+						logger.Handler = apexHookHandler{logger.Handler, func(e *apex.Entry) {
+							e.Fields[key] = 1
+						}}
+					case metricsHook:
+						logger.Handler = apexHookHandler{logger.Handler, func(e *apex.Entry) {
+							counter.inc(int(e.Level))
+						}}
+					}
+				}
+				return func() {
+					logger.Info(getMessage(0))
+				}
+			},
+		},
+		{
+			name: "go-kit/kit/log",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				logger := newKitLog()
+				for i := 0; i < n; i++ {
+					key := _hookKeys[i]
+					next := logger
+					switch kind {
+					case noOpHook:
+						logger = log.LoggerFunc(func(keyvals ...interface{}) error {
+							return next.Log(keyvals...)
+						})
+					case fieldHook:
+						logger = log.LoggerFunc(func(keyvals ...interface{}) error {
+							return next.Log(append(keyvals, key, 1)...)
+						})
+					case metricsHook:
+						logger = log.LoggerFunc(func(keyvals ...interface{}) error {
+							counter.inc(kitLevelSlot(keyvals))
+							return next.Log(keyvals...)
+						})
+					}
+				}
+				logger = level.Info(logger)
+				return func() {
+					_ = logger.Log("msg", getMessage(0))
+				}
+			},
+		},
+		{
+			name: "inconshreveable/log15",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				logger := newLog15()
+				handler := logger.GetHandler()
+				for i := 0; i < n; i++ {
+					key := _hookKeys[i]
+					next := handler
+					switch kind {
+					case noOpHook:
+						handler = log15.FuncHandler(func(r *log15.Record) error {
+							return next.Log(r)
+						})
+					case fieldHook:
+						handler = log15.FuncHandler(func(r *log15.Record) error {
+							r.Ctx = append(r.Ctx, key, 1)
+							return next.Log(r)
+						})
+					case metricsHook:
+						handler = log15.FuncHandler(func(r *log15.Record) error {
+							counter.inc(int(r.Lvl))
+							return next.Log(r)
+						})
+					}
+				}
+				logger.SetHandler(handler)
+				return func() {
+					logger.Info(getMessage(0))
+				}
+			},
+		},
+		{
+			name: "sirupsen/logrus",
+			build: func(kind hookKind, n int, counter *levelCounter) func() {
+				logger := newLogrus()
+				for i := 0; i < n; i++ {
+					logger.AddHook(&logrusHook{kind: kind, key: _hookKeys[i], counter: counter})
+				}
+				return func() {
+					logger.Info(getMessage(0))
+				}
+			},
+		},
+	}
+}
+
+func kitLevelSlot(keyvals []interface{}) int {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] != level.Key() {
+			continue
+		}
+		if v, ok := keyvals[i+1].(level.Value); ok {
+			switch v.String() {
+			case "debug":
+				return 0
+			case "info":
+				return 1
+			case "warn":
+				return 2
+			case "error":
+				return 3
+			}
+		}
+	}
+	return 7
+}
+
+func BenchmarkHooks(b *testing.B) {
+	b.Logf("Logging through a number of hooks or handler middlewares.")
+	for _, l := range hookedLoggers() {
+		l := l
+		// Without hooks both kinds are the same logger, so it runs once.
+		for _, n := range _hookCounts {
+			for _, kind := range []hookKind{noOpHook, fieldHook} {
+				name := l.name + "/" + kind.String() + "/" + strconv.Itoa(n)
+				if n == 0 {
+					if kind != noOpHook {
+						continue
+					}
+					name = l.name + "/0"
+				}
+				logFn := l.build(kind, n, nil)
+				if logFn == nil {
+					continue
+				}
+				b.Run(name, func(b *testing.B) {
+					defer reportScenario(b, nil)()
+					b.ResetTimer()
+					b.RunParallel(func(pb *testing.PB) {
+						for pb.Next() {
+							logFn()
+						}
+					})
+				})
+			}
+		}
+
+		logFn := l.build(metricsHook, 1, &levelCounter{})
+		if logFn == nil {
+			continue
+		}
+		b.Run(l.name+"/"+metricsHook.String(), func(b *testing.B) {
+			defer reportScenario(b, nil)()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					logFn()
+				}
+			})
+		})
+	}
+}
+
+func TestHookCounts(t *testing.T) {
+	const records = 10
+	for _, l := range hookedLoggers() {
+		for _, n := range []int{1, 5} {
+			counter := &levelCounter{}
+			logFn := l.build(metricsHook, n, counter)
+			if logFn == nil {
+				continue
+			}
+			for i := 0; i < records; i++ {
+				logFn()
+			}
+			if got, want := counter.total(), int64(n*records); got != want {
+				t.Errorf("%s: %d metrics hooks counted %d records, want %d", l.name, n, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package benchfmt reads the output of go test -bench for the commands of
+// this repository.
+package benchfmt
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Metric is one value of a benchmark result, e.g. 85.29 ns/op.
+type Metric struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// Result is a single benchmark line.
+type Result struct {
+	// Name is the benchmark name without its GOMAXPROCS suffix, so runs on
+	// machines with different core counts compare.
+	Name       string   `json:"name"`
+	Procs      int      `json:"procs"`
+	Iterations int      `json:"iterations"`
+	Metrics    []Metric `json:"metrics"`
+}
+
+// Value returns the value of the metric with the given unit.
+func (r Result) Value(unit string) (float64, bool) {
+	for _, m := range r.Metrics {
+		if m.Unit == unit {
+			return m.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Set is the output of one go test run: the configuration lines such as
+// "goos: linux" and the results.
+type Set struct {
+	Config  map[string]string `json:"env"`
+	Results []Result          `json:"results"`
+}
+
+// Libraries are the names the benchmarks give the libraries in their
+// sub-benchmarks, where a variant may follow them as in Zap.Check.
+var Libraries = []string{
+	"Logy",
+	"exp/slog",
+	"Zap",
+	"rs/zerolog",
+	"apex/log",
+	"go-kit/kit/log",
+	"inconshreveable/log15",
+	"sirupsen/logrus",
+	"stdlib",
+}
+
+var (
+	_configLine  = regexp.MustCompile(`^([a-z][^\s:]*):\s+(.*)$`)
+	_procsSuffix = regexp.MustCompile(`-(\d+)$`)
+)
+
+// Parse reads a benchmark output, skipping the lines that are neither results
+// nor configuration.
+func Parse(r io.Reader) (*Set, error) {
+	set := &Set{Config: make(map[string]string)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := _configLine.FindStringSubmatch(line); m != nil {
+			set.Config[m[1]] = strings.TrimSpace(m[2])
+			continue
+		}
+		if res, ok := parseResult(line); ok {
+			set.Results = append(set.Results, res)
+		}
+	}
+	set.splitProcs()
+	return set, scanner.Err()
+}
+
+// splitProcs moves the GOMAXPROCS suffix of the result names into Procs. go
+// test leaves it out when GOMAXPROCS is 1, so a trailing -N, N > 1, is only
+// taken for one when N is the gomaxprocs of the run, or when the name without
+// it is a result too, as with -cpu 1,4. Output lacking the gomaxprocs line has
+// it taken for one when every result has one. A sub-benchmark name ending in
+// -N keeps it otherwise.
+func (s *Set) splitProcs() {
+	gomaxprocs, known := s.Config["gomaxprocs"]
+	names := make(map[string]bool, len(s.Results))
+	all := !known
+	for _, res := range s.Results {
+		names[res.Name] = true
+		all = all && _procsSuffix.MatchString(res.Name)
+	}
+	for i := range s.Results {
+		res := &s.Results[i]
+		m := _procsSuffix.FindStringSubmatchIndex(res.Name)
+		if m == nil {
+			continue
+		}
+		name, procs := res.Name[:m[0]], res.Name[m[2]:m[3]]
+		if procs == "1" {
+			continue
+		}
+		if all || procs == gomaxprocs || names[name] {
+			res.Name = name
+			res.Procs, _ = strconv.Atoi(procs)
+		}
+	}
+}
+
+func parseResult(line string) (Result, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return Result{}, false
+	}
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Result{}, false
+	}
+
+	res := Result{Name: fields[0], Procs: 1, Iterations: iterations}
+	for i := 2; i+1 < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false
+		}
+		res.Metrics = append(res.Metrics, Metric{Value: value, Unit: fields[i+1]})
+	}
+	return res, true
+}
@@ -0,0 +1,103 @@
+package benchfmt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const _output = `goos: linux
+goarch: amd64
+pkg: github.com/procyon-projects/logy/benchmarks
+cpu: Intel(R) Xeon(R) Processor
+gomaxprocs: 8
+module/github.com/procyon-projects/logy: v0.1.0
+module/github.com/BurntSushi/toml: v1.2.1
+BenchmarkWithContext
+    scenario_bench_test.go:12: Logging with some accumulated context.
+BenchmarkWithContext/Logy-8         	 1000000	        85.29 ns/op	       0 B/op	       0 allocs/op
+BenchmarkWithContext/Zap.Check      	 1000000	        92.50 ns/op	      1574 bytes/op
+PASS
+ok  	github.com/procyon-projects/logy/benchmarks	0.591s
+`
+
+func TestParse(t *testing.T) {
+	set, err := Parse(strings.NewReader(_output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantConfig := map[string]string{
+		"goos":       "linux",
+		"goarch":     "amd64",
+		"pkg":        "github.com/procyon-projects/logy/benchmarks",
+		"cpu":        "Intel(R) Xeon(R) Processor",
+		"gomaxprocs": "8",
+		"module/github.com/procyon-projects/logy": "v0.1.0",
+		"module/github.com/BurntSushi/toml":       "v1.2.1",
+	}
+	if !reflect.DeepEqual(set.Config, wantConfig) {
+		t.Errorf("got config %v, want %v", set.Config, wantConfig)
+	}
+
+	wantResults := []Result{
+		{Name: "BenchmarkWithContext/Logy", Procs: 8, Iterations: 1000000, Metrics: []Metric{{85.29, "ns/op"}, {0, "B/op"}, {0, "allocs/op"}}},
+		{Name: "BenchmarkWithContext/Zap.Check", Procs: 1, Iterations: 1000000, Metrics: []Metric{{92.50, "ns/op"}, {1574, "bytes/op"}}},
+	}
+	if !reflect.DeepEqual(set.Results, wantResults) {
+		t.Errorf("got results %v, want %v", set.Results, wantResults)
+	}
+}
+
+func TestParseProcs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Result
+	}{
+		{
+			name: "GOMAXPROCS=1",
+			output: `gomaxprocs: 1
+BenchmarkTee/Zap-2 	 1000 	 85 ns/op
+BenchmarkTee/Zap-4 	 1000 	 92 ns/op
+`,
+			want: []Result{
+				{Name: "BenchmarkTee/Zap-2", Procs: 1, Iterations: 1000, Metrics: []Metric{{85, "ns/op"}}},
+				{Name: "BenchmarkTee/Zap-4", Procs: 1, Iterations: 1000, Metrics: []Metric{{92, "ns/op"}}},
+			},
+		},
+		{
+			name: "-cpu 1,4",
+			output: `gomaxprocs: 8
+BenchmarkTee/Zap-2 	 1000 	 85 ns/op
+BenchmarkTee/Zap-2-4 	 1000 	 92 ns/op
+`,
+			want: []Result{
+				{Name: "BenchmarkTee/Zap-2", Procs: 1, Iterations: 1000, Metrics: []Metric{{85, "ns/op"}}},
+				{Name: "BenchmarkTee/Zap-2", Procs: 4, Iterations: 1000, Metrics: []Metric{{92, "ns/op"}}},
+			},
+		},
+		{
+			name: "-cpu 2,4",
+			output: `BenchmarkTee/Zap-2 	 1000 	 85 ns/op
+BenchmarkTee/Zap-4 	 1000 	 92 ns/op
+`,
+			want: []Result{
+				{Name: "BenchmarkTee/Zap", Procs: 2, Iterations: 1000, Metrics: []Metric{{85, "ns/op"}}},
+				{Name: "BenchmarkTee/Zap", Procs: 4, Iterations: 1000, Metrics: []Metric{{92, "ns/op"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := Parse(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(set.Results, tt.want) {
+				t.Errorf("got %v, want %v", set.Results, tt.want)
+			}
+		})
+	}
+}
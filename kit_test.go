@@ -2,10 +2,33 @@ package benchmarks
 
 import (
 	"io"
+	"strconv"
 
 	"github.com/go-kit/log"
 )
 
 func newKitLog(fields ...interface{}) log.Logger {
-	return log.With(log.NewJSONLogger(io.Discard), fields...)
+	return newKitLogTo(io.Discard, fields...)
+}
+
+func newKitLogTo(w io.Writer, fields ...interface{}) log.Logger {
+	return log.With(log.NewJSONLogger(w), fields...)
+}
+
+// keyvals flattens u into key-value pairs under prefix, the representation
+// go-kit and log15 encode without reflection or String.
+func (u *user) keyvals(prefix string) []interface{} {
+	return []interface{}{
+		prefix + ".name", u.Name,
+		prefix + ".email", u.Email,
+		prefix + ".created_at", u.CreatedAt,
+	}
+}
+
+func (uu users) keyvals(prefix string) []interface{} {
+	keyvals := make([]interface{}, 0, len(uu)*6)
+	for i, u := range uu {
+		keyvals = append(keyvals, u.keyvals(prefix+"."+strconv.Itoa(i))...)
+	}
+	return keyvals
 }
@@ -0,0 +1,228 @@
+package benchmarks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apex/log"
+	kitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slog"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// _bodyEvals counts the calls of expensiveBody.
+var _bodyEvals int64
+
+// expensiveBody stands for serialising a request body, the kind of field which
+// shouldn't be paid for when its record is dropped.
+func expensiveBody() []byte {
+	atomic.AddInt64(&_bodyEvals, 1)
+	body, _ := json.Marshal(_tenPlainUsers)
+	return body
+}
+
+// lazyBody calls expensiveBody only when a library encodes it, through
+// whichever interface the library resolves values with.
+type lazyBody struct{}
+
+func (lazyBody) String() string               { return string(expensiveBody()) }
+func (lazyBody) MarshalJSON() ([]byte, error) { return json.Marshal(string(expensiveBody())) }
+func (lazyBody) LogValue() slog.Value         { return slog.StringValue(string(expensiveBody())) }
+func (lazyBody) MarshalObject(encoder logy.ObjectEncoder) error {
+	return bodyObject(expensiveBody()).MarshalObject(encoder)
+}
+
+// bodyObject is a body serialised up front, encoded by logy the way it encodes
+// lazyBody as it can only defer a value to a marshaler.
+type bodyObject string
+
+func (b bodyObject) MarshalObject(encoder logy.ObjectEncoder) error {
+	encoder.AddString("body", string(b))
+	return nil
+}
+
+// lazyLogger logs a record with a body at info level when enabled and at debug
+// level otherwise, its loggers only letting info through. skips tells whether
+// the lazy body is expected to be left alone by disabled records, and lazyPath
+// names the lazy path when it isn't a lazy field of the library.
+type lazyLogger struct {
+	name     string
+	skips    bool
+	lazyPath string
+	eager    func(enabled bool)
+	lazy     func(enabled bool)
+}
+
+func lazyLoggers(w io.Writer) []lazyLogger {
+	logyLogger := logy.Get()
+	slogLogger := slog.New(slog.HandlerOptions{Level: slog.LevelInfo}.NewJSONHandler(w))
+	zapLogger := newZapLoggerTo(w, zap.InfoLevel)
+	zerologLogger := newZerologTo(w).Level(zerolog.InfoLevel)
+	apexLogger := newApexLogTo(w)
+	apexLogger.Level = log.InfoLevel
+	kitLogger := level.NewFilter(newKitLogTo(w), level.AllowInfo())
+	log15Logger := log15.New()
+	log15Logger.SetHandler(log15.LvlFilterHandler(log15.LvlInfo, log15.StreamHandler(w, log15.JsonFormat())))
+	logrusLogger := newLogrusTo(w)
+	logrusLogger.Level = logrus.InfoLevel
+
+	return []lazyLogger{
+		// logy has no lazy field of its own, the body is deferred to a
+		// marshaler called when a record gets encoded.
+		{"Logy", true, "MarshalerDeferred", func(enabled bool) {
+			ctx := logy.WithValue(logy.WithContextFields(context.Background()), "body", bodyObject(expensiveBody()))
+			if enabled {
+				logyLogger.I(ctx, getMessage(0))
+			} else {
+				logyLogger.D(ctx, getMessage(0))
+			}
+		}, func(enabled bool) {
+			ctx := logy.WithValue(logy.WithContextFields(context.Background()), "body", lazyBody{})
+			if enabled {
+				logyLogger.I(ctx, getMessage(0))
+			} else {
+				logyLogger.D(ctx, getMessage(0))
+			}
+		}},
+		{"exp/slog", true, "", func(enabled bool) {
+			slogLogger.LogAttrs(lazyLevel(enabled, slog.LevelInfo, slog.LevelDebug), getMessage(0),
+				slog.String("body", string(expensiveBody())))
+		}, func(enabled bool) {
+			slogLogger.LogAttrs(lazyLevel(enabled, slog.LevelInfo, slog.LevelDebug), getMessage(0),
+				slog.Any("body", lazyBody{}))
+		}},
+		{"Zap", true, "", func(enabled bool) {
+			zapLogger.Log(lazyLevel(enabled, zap.InfoLevel, zap.DebugLevel), getMessage(0),
+				zap.ByteString("body", expensiveBody()))
+		}, func(enabled bool) {
+			zapLogger.Log(lazyLevel(enabled, zap.InfoLevel, zap.DebugLevel), getMessage(0),
+				zap.Stringer("body", lazyBody{}))
+		}},
+		{"rs/zerolog", true, "", func(enabled bool) {
+			zerologLogger.WithLevel(lazyLevel(enabled, zerolog.InfoLevel, zerolog.DebugLevel)).
+				Bytes("body", expensiveBody()).Msg(getMessage(0))
+		}, func(enabled bool) {
+			zerologLogger.WithLevel(lazyLevel(enabled, zerolog.InfoLevel, zerolog.DebugLevel)).
+				Func(func(e *zerolog.Event) { e.Bytes("body", expensiveBody()) }).Msg(getMessage(0))
+		}},
+		{"apex/log", true, "", func(enabled bool) {
+			entry := apexLogger.WithField("body", string(expensiveBody()))
+			lazyLevel(enabled, entry.Info, entry.Debug)(getMessage(0))
+		}, func(enabled bool) {
+			entry := apexLogger.WithField("body", lazyBody{})
+			lazyLevel(enabled, entry.Info, entry.Debug)(getMessage(0))
+		}},
+		// Valuers are bound before the level filter sees the record, so
+		// go-kit evaluates them for dropped records too.
+		{"go-kit/kit/log", false, "", func(enabled bool) {
+			_ = lazyLevel(enabled, level.Info, level.Debug)(kitLogger).Log("msg", getMessage(0), "body", string(expensiveBody()))
+		}, func(enabled bool) {
+			logger := kitlog.With(kitLogger, "body", kitlog.Valuer(func() interface{} { return string(expensiveBody()) }))
+			_ = lazyLevel(enabled, level.Info, level.Debug)(logger).Log("msg", getMessage(0))
+		}},
+		{"inconshreveable/log15", true, "", func(enabled bool) {
+			lazyLevel(enabled, log15Logger.Info, log15Logger.Debug)(getMessage(0), "body", string(expensiveBody()))
+		}, func(enabled bool) {
+			lazyLevel(enabled, log15Logger.Info, log15Logger.Debug)(getMessage(0),
+				"body", log15.Lazy{Fn: func() string { return string(expensiveBody()) }})
+		}},
+		{"sirupsen/logrus", true, "", func(enabled bool) {
+			logrusLogger.WithField("body", string(expensiveBody())).
+				Log(lazyLevel(enabled, logrus.InfoLevel, logrus.DebugLevel), getMessage(0))
+		}, func(enabled bool) {
+			logrusLogger.WithField("body", lazyBody{}).
+				Log(lazyLevel(enabled, logrus.InfoLevel, logrus.DebugLevel), getMessage(0))
+		}},
+	}
+}
+
+func lazyLevel[T any](enabled bool, info, debug T) T {
+	if enabled {
+		return info
+	}
+	return debug
+}
+
+func BenchmarkLazyFields(b *testing.B) {
+	b.Logf("Logging a costly body eagerly and through each library's lazy field, at enabled and disabled levels.")
+	d := &Discarder{}
+	defer loadLogyIntoDevNull(b, &logy.Config{Level: logy.LevelInfo, Console: newLogyJsonConsole()})()
+
+	for _, state := range []struct {
+		name    string
+		enabled bool
+	}{{"Enabled", true}, {"Disabled", false}} {
+		for _, l := range lazyLoggers(d) {
+			lazyPath := l.lazyPath
+			if lazyPath == "" {
+				lazyPath = "Lazy"
+			}
+			for _, path := range []struct {
+				name string
+				log  func(enabled bool)
+			}{{"Eager", l.eager}, {lazyPath, l.lazy}} {
+				enabled, log := state.enabled, path.log
+				b.Run(state.name+"/"+path.name+"/"+l.name, func(b *testing.B) {
+					defer reportScenario(b, d)()
+					evals := atomic.LoadInt64(&_bodyEvals)
+					b.ReportAllocs()
+					b.ResetTimer()
+					b.RunParallel(func(pb *testing.PB) {
+						for pb.Next() {
+							log(enabled)
+						}
+					})
+					b.StopTimer()
+					b.ReportMetric(float64(atomic.LoadInt64(&_bodyEvals)-evals)/float64(b.N), "evals/op")
+				})
+			}
+		}
+	}
+}
+
+func TestLazyFieldsSkipped(t *testing.T) {
+	// logy has to encode its records for its marshalers to be called.
+	defer loadLogyIntoDevNull(t, &logy.Config{Level: logy.LevelInfo, Console: newLogyJsonConsole()})()
+
+	for _, l := range lazyLoggers(io.Discard) {
+		evals := atomic.LoadInt64(&_bodyEvals)
+		l.lazy(true)
+		if n := atomic.LoadInt64(&_bodyEvals) - evals; n != 1 {
+			t.Errorf("%s evaluated the lazy body %d times for an enabled record, want once", l.name, n)
+		}
+
+		evals = atomic.LoadInt64(&_bodyEvals)
+		l.lazy(false)
+		switch n := atomic.LoadInt64(&_bodyEvals) - evals; {
+		case n != 0 && l.skips:
+			t.Errorf("%s evaluated the lazy body %d times for a disabled record", l.name, n)
+		case n != 0:
+			t.Logf("%s evaluated the lazy body %d times for a disabled record", l.name, n)
+		}
+	}
+}
+
+// TestLazyFieldsShape checks the eager and lazy paths log the same body under
+// the same key, so the benchmark compares the cost of the same record.
+func TestLazyFieldsShape(t *testing.T) {
+	w := &switchWriter{}
+	for _, l := range lazyLoggers(w) {
+		eager, ok := loggedField(t, w, l.name, "body", func() { l.eager(true) })
+		if !ok {
+			t.Errorf("%s logged no body", l.name)
+		}
+		lazy, _ := loggedField(t, w, l.name, "body", func() { l.lazy(true) })
+		if !reflect.DeepEqual(eager, lazy) {
+			t.Errorf("%s logged the body %v eagerly and %v lazily", l.name, eager, lazy)
+		}
+	}
+}
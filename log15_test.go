@@ -7,7 +7,11 @@ import (
 )
 
 func newLog15() log15.Logger {
+	return newLog15To(io.Discard)
+}
+
+func newLog15To(w io.Writer) log15.Logger {
 	logger := log15.New()
-	logger.SetHandler(log15.StreamHandler(io.Discard, log15.JsonFormat()))
+	logger.SetHandler(log15.StreamHandler(w, log15.JsonFormat()))
 	return logger
 }
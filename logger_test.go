@@ -8,6 +8,7 @@ import (
 
 func BenchmarkObtainLogger(b *testing.B) {
 	b.Run("logy.Get", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
 				logy.Get()
@@ -15,6 +16,7 @@ func BenchmarkObtainLogger(b *testing.B) {
 		})
 	})
 	b.Run("logy.Named", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
 				logy.Named("github.com/procyon-projects/logy/test/benchmark")
@@ -22,6 +24,7 @@ func BenchmarkObtainLogger(b *testing.B) {
 		})
 	})
 	b.Run("logy.Of", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
 				logy.Of[http.Client]()
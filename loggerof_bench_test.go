@@ -0,0 +1,143 @@
+package benchmarks
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/procyon-projects/logy"
+	"go.uber.org/zap"
+)
+
+type box[T any] struct {
+	value T
+}
+
+type pair[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// loggerOf is a type to get a logger for, with logy.Of and with the
+// zap.Named(reflect.TypeOf(x).String()) pattern.
+type loggerOf struct {
+	name     string
+	typeName string // the name zap gets
+	logy     func() *logy.Logger
+	zap      func(base *zap.Logger) *zap.Logger
+}
+
+func loggerOfType[T any](name string) loggerOf {
+	return loggerOf{
+		name:     name,
+		typeName: reflect.TypeOf((*T)(nil)).Elem().String(),
+		logy:     logy.Of[T],
+		zap: func(base *zap.Logger) *zap.Logger {
+			var x T
+			return base.Named(reflect.TypeOf(x).String())
+		},
+	}
+}
+
+var _loggersOf = []loggerOf{
+	loggerOfType[http.Client]("http.Client"),
+	loggerOfType[user]("user"),
+	loggerOfType[time.Duration]("time.Duration"),
+	loggerOfType[*http.Client]("*http.Client"),
+	loggerOfType[*user]("*user"),
+	loggerOfType[[]user]("[]user"),
+	loggerOfType[map[string][]user]("map[string][]user"),
+	loggerOfType[box[int]]("box[int]"),
+	loggerOfType[box[user]]("box[user]"),
+	loggerOfType[box[*user]]("box[*user]"),
+	loggerOfType[box[map[string][]user]]("box[map[string][]user]"),
+	loggerOfType[pair[string, box[int]]]("pair[string,box[int]]"),
+}
+
+// firstUse is the cost of the first logy.Of call for a type, which derives the
+// name of its logger and registers it. Later calls only look the logger up.
+type firstUse struct {
+	ns, allocs float64
+}
+
+// _firstUses are the first logy.Of calls for the types of _loggersOf, timed
+// while the package initialises so that no test or benchmark got their loggers
+// before. A type has a single first call per process, so each is one sample
+// whatever -count and -benchtime.
+var _firstUses = timeFirstUses(_loggersOf)
+
+func timeFirstUses(loggers []loggerOf) map[string]firstUse {
+	uses := make(map[string]firstUse, len(loggers))
+	var before, after runtime.MemStats
+	for _, tt := range loggers {
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		tt.logy()
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		uses[tt.name] = firstUse{ns: float64(elapsed.Nanoseconds()), allocs: float64(after.Mallocs - before.Mallocs)}
+	}
+	return uses
+}
+
+func BenchmarkLoggerOf(b *testing.B) {
+	b.Logf("Getting the logger of a type, with logy.Of and with zap.Named(reflect.TypeOf(x).String()).")
+	base := newZapLogger(zap.DebugLevel)
+
+	for _, tt := range _loggersOf {
+		tt := tt
+		// The timed calls only look the logger up, the first use is reported
+		// next to them.
+		b.Run(tt.name+"/logy.Of", func(b *testing.B) {
+			defer reportGC(b)()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					tt.logy()
+				}
+			})
+			first := _firstUses[tt.name]
+			b.ReportMetric(first.ns, "first-use-ns")
+			b.ReportMetric(first.allocs, "first-use-allocs")
+		})
+		b.Run(tt.name+"/zap.Named", func(b *testing.B) {
+			defer reportGC(b)()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					tt.zap(base)
+				}
+			})
+		})
+	}
+}
+
+func TestLoggerOf(t *testing.T) {
+	owners := make(map[string]loggerOf)
+	for _, tt := range _loggersOf {
+		logger := tt.logy()
+		if logger == nil {
+			t.Fatalf("%s: logy.Of returned nil", tt.name)
+		}
+		if again := tt.logy(); again != logger {
+			t.Errorf("%s: logy.Of returned another logger the second time", tt.name)
+		}
+
+		// The derived name is stable when it leads back to the same logger.
+		name := logger.Name()
+		if name == "" {
+			t.Errorf("%s: logy.Of named the logger \"\"", tt.name)
+		} else if named := logy.Named(name); named != logger {
+			t.Errorf("%s: logy.Named(%q) isn't the logger logy.Of named so", tt.name, name)
+		}
+		if owner, ok := owners[name]; ok {
+			t.Errorf("%s and %s share the logger %q, zap names them %q and %q", owner.name, tt.name, name, owner.typeName, tt.typeName)
+			continue
+		}
+		owners[name] = tt
+	}
+}
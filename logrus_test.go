@@ -6,15 +6,19 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func newDisabledLogrus() *logrus.Logger {
-	logger := newLogrus()
+func newDisabledLogrus(w io.Writer) *logrus.Logger {
+	logger := newLogrusTo(w)
 	logger.Level = logrus.ErrorLevel
 	return logger
 }
 
 func newLogrus() *logrus.Logger {
+	return newLogrusTo(io.Discard)
+}
+
+func newLogrusTo(w io.Writer) *logrus.Logger {
 	return &logrus.Logger{
-		Out:       io.Discard,
+		Out:       w,
 		Formatter: new(logrus.JSONFormatter),
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.DebugLevel,
@@ -0,0 +1,128 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/procyon-projects/logy"
+)
+
+func newLogyJsonConsole() *logy.ConsoleConfig {
+	return &logy.ConsoleConfig{
+		Enabled: true,
+		Format:  "%d %p %c : %m%s%n",
+		Json: &logy.JsonConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// The logy API the scenarios rely on besides what the original benchmarks use,
+// referenced in one place so a logy release missing any of it fails to build
+// here first. See "The logy API" in the README.
+var (
+	_ = logy.TargetStdout
+	_ = []logy.Level{logy.LevelOff, logy.LevelWarn, logy.LevelInfo, logy.LevelTrace}
+	_ = logy.JsonConfig{KeyOverrides: logy.KeyOverrides{}, ExcludedKeys: []string{}}
+	_ = logy.Config{Handlers: logy.Handlers{}, File: &logy.FileConfig{}, Package: map[string]*logy.PackageConfig{}}
+	_ = logy.PackageConfig{Level: logy.LevelInfo, UseParentHandlers: true}
+	_ = (*logy.Logger).Level
+	_ = (*logy.Logger).IsLoggable
+	_ = (*logy.Logger).D
+	_ = (*logy.Logger).Name
+	_ = []logy.ObjectMarshaler{}
+	_ = []logy.ArrayMarshaler{}
+)
+
+// loadLogyInto loads config with logy's console handler writing straight into
+// f. logy can only target stdout, stderr or nothing, so os.Stdout is swapped
+// for f while the configuration is loaded, see TestRedirectLogy. The returned
+// function detaches logy from f.
+func loadLogyInto(f *os.File, config *logy.Config) func() {
+	config.Console.Target = logy.TargetStdout
+	stdout := os.Stdout
+	os.Stdout = f
+	_ = logy.LoadConfig(config)
+	os.Stdout = stdout
+
+	return func() {
+		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard, Enabled: true}})
+	}
+}
+
+// loadLogyIntoDevNull loads config with logy's console handler writing into
+// the null device, for the benchmarks measuring what logy pays to encode its
+// records, which its discard target may not do. The returned function
+// detaches logy from it.
+func loadLogyIntoDevNull(tb testing.TB, config *logy.Config) func() {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	detach := loadLogyInto(devNull, config)
+	return func() {
+		detach()
+		_ = devNull.Close()
+	}
+}
+
+// redirectLogy loads config with logy's console handler writing to w, through
+// a pipe everything read from is copied into w. Unlike the other libraries,
+// logy pays a write syscall per record there, so benchmarks measuring logy this
+// way name it Logy.Pipe. The returned function detaches logy from the pipe and
+// waits for the copy to finish.
+func redirectLogy(w io.Writer, config *logy.Config) func() {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Keep draining if w fails, logy would block on a full pipe otherwise.
+		if _, err := io.Copy(w, r); err != nil {
+			_, _ = io.Copy(io.Discard, r)
+		}
+	}()
+
+	detach := loadLogyInto(pw, config)
+	return func() {
+		detach()
+		_ = pw.Close()
+		<-done
+		_ = r.Close()
+	}
+}
+
+// TestRedirectLogy checks logy keeps writing into the stdout it was configured
+// with, which redirectLogy and loadLogyInto rely on.
+func TestRedirectLogy(t *testing.T) {
+	var out bytes.Buffer
+	restore := redirectLogy(&out, &logy.Config{Level: logy.LevelDebug, Console: newLogyJsonConsole()})
+	logy.Get().Info(getMessage(1))
+	restore()
+
+	if !strings.Contains(out.String(), getMessage(1)) {
+		t.Errorf("the record didn't reach the redirected stdout, got %q", out.String())
+	}
+}
+
+func fakeLogyContext() context.Context {
+	ctx := logy.WithContextFields(context.Background())
+	ctx = logy.WithValue(ctx, "int", _tenInts[0])
+	ctx = logy.WithValue(ctx, "ints", _tenInts)
+	ctx = logy.WithValue(ctx, "string", _tenStrings[0])
+	ctx = logy.WithValue(ctx, "strings", _tenStrings)
+	ctx = logy.WithValue(ctx, "time", _tenTimes[0])
+	ctx = logy.WithValue(ctx, "times", _tenTimes)
+	ctx = logy.WithValue(ctx, "user1", _oneUser)
+	ctx = logy.WithValue(ctx, "user2", _oneUser)
+	ctx = logy.WithValue(ctx, "users", _tenUsers)
+	ctx = logy.WithValue(ctx, "error", errExample)
+	return ctx
+}
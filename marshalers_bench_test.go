@@ -0,0 +1,295 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/procyon-projects/logy"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slog"
+)
+
+// jsonUser is user with the marshalers of the libraries relying on
+// encoding/json or on slog.LogValuer as well, kept apart from user so the
+// other scenarios keep measuring the libraries' fallbacks for it.
+type jsonUser struct {
+	*user
+}
+
+// jsonUsers is users with MarshalJSON.
+type jsonUsers struct {
+	users
+}
+
+var (
+	_oneJSONUser  = jsonUser{_oneUser}
+	_tenJSONUsers = jsonUsers{_tenUsers}
+)
+
+// MarshalJSON encodes u without reflection for logrus and apex/log.
+func (u jsonUser) MarshalJSON() ([]byte, error) {
+	return u.appendJSON(make([]byte, 0, 96)), nil
+}
+
+func (u jsonUser) appendJSON(b []byte) []byte {
+	b = append(b, `{"name":`...)
+	b = appendJSONString(b, u.Name)
+	b = append(b, `,"email":`...)
+	b = appendJSONString(b, u.Email)
+	b = append(b, `,"created_at":"`...)
+	b = u.CreatedAt.AppendFormat(b, time.RFC3339Nano)
+	return append(b, `"}`...)
+}
+
+func (uu jsonUsers) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 1+len(uu.users)*97)
+	b = append(b, '[')
+	for i, u := range uu.users {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = jsonUser{u}.appendJSON(b)
+	}
+	return append(b, ']'), nil
+}
+
+// LogValue lets slog encode u as a group instead of through encoding/json.
+func (u jsonUser) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", u.Name),
+		slog.String("email", u.Email),
+		slog.Time("created_at", u.CreatedAt),
+	)
+}
+
+// appendJSONString appends s to b as a JSON string, escaped like encoding/json
+// does, invalid UTF-8 replaced with U+FFFD.
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20 || c == '<' || c == '>' || c == '&':
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xf])
+		default:
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
+
+// marshalerLogger logs a single field with a library, once through its
+// fallback for any value and once through the marshaler the library
+// understands best for the user fixtures, the ten users if array. flattens
+// tells whether that marshaler writes the fields of the users under keys of
+// their own, go-kit and log15 having no nested values.
+type marshalerLogger struct {
+	name      string
+	flattens  bool
+	fallback  func(key string, v interface{})
+	marshaler func(key string, array bool)
+}
+
+func marshalerLoggers(w io.Writer) []marshalerLogger {
+	l := newLibraryLoggers(w)
+
+	// fixture returns the user fixtures, with the marshalers of the libraries
+	// relying on encoding/json or on slog.LogValuer if json.
+	fixture := func(array, json bool) interface{} {
+		switch {
+		case array && json:
+			return _tenJSONUsers
+		case array:
+			return _tenUsers
+		case json:
+			return _oneJSONUser
+		}
+		return _oneUser
+	}
+	byKey := func(log func(key string, v interface{}), json bool) func(key string, array bool) {
+		return func(key string, array bool) {
+			log(key, fixture(array, json))
+		}
+	}
+	logyLog := func(key string, v interface{}) {
+		l.logy.I(logy.WithValue(logy.WithContextFields(context.Background()), key, v), getMessage(0))
+	}
+	slogLog := func(key string, v interface{}) {
+		l.slog.LogAttrs(slog.LevelInfo, getMessage(0), slog.Any(key, v))
+	}
+	apexLog := func(key string, v interface{}) {
+		l.apex.WithField(key, v).Info(getMessage(0))
+	}
+	logrusLog := func(key string, v interface{}) {
+		l.logrus.WithFields(logrus.Fields{key: v}).Info(getMessage(0))
+	}
+	keyvals := func(key string, array bool) []interface{} {
+		if array {
+			return _tenUsers.keyvals(key)
+		}
+		return _oneUser.keyvals(key)
+	}
+
+	return []marshalerLogger{
+		{name: "Logy", fallback: logyLog, marshaler: byKey(logyLog, false)},
+		{name: "exp/slog", fallback: slogLog, marshaler: byKey(slogLog, true)},
+		{name: "Zap", fallback: func(key string, v interface{}) {
+			l.zap.Info(getMessage(0), zap.Reflect(key, v))
+		}, marshaler: func(key string, array bool) {
+			if array {
+				l.zap.Info(getMessage(0), zap.Array(key, _tenUsers))
+				return
+			}
+			l.zap.Info(getMessage(0), zap.Object(key, _oneUser))
+		}},
+		{name: "rs/zerolog", fallback: func(key string, v interface{}) {
+			l.zerolog.Info().Interface(key, v).Msg(getMessage(0))
+		}, marshaler: func(key string, array bool) {
+			if array {
+				l.zerolog.Info().Array(key, _tenUsers).Msg(getMessage(0))
+				return
+			}
+			l.zerolog.Info().Object(key, _oneUser).Msg(getMessage(0))
+		}},
+		{name: "apex/log", fallback: apexLog, marshaler: byKey(apexLog, true)},
+		{name: "go-kit/kit/log", flattens: true, fallback: func(key string, v interface{}) {
+			_ = l.kit.Log("msg", getMessage(0), key, v)
+		}, marshaler: func(key string, array bool) {
+			_ = l.kit.Log(append([]interface{}{"msg", getMessage(0)}, keyvals(key, array)...)...)
+		}},
+		{name: "inconshreveable/log15", flattens: true, fallback: func(key string, v interface{}) {
+			l.log15.Info(getMessage(0), key, v)
+		}, marshaler: func(key string, array bool) {
+			l.log15.Info(getMessage(0), keyvals(key, array)...)
+		}},
+		{name: "sirupsen/logrus", fallback: logrusLog, marshaler: byKey(logrusLog, true)},
+	}
+}
+
+func BenchmarkMarshalers(b *testing.B) {
+	b.Logf("Logging values without marshalers through each library's fallback, next to the user fixtures through its marshaler.")
+	d := &Discarder{}
+	defer loadLogyIntoDevNull(b, &logy.Config{Level: logy.LevelDebug, Console: newLogyJsonConsole()})()
+
+	for _, shape := range _fallbackShapes {
+		for _, l := range marshalerLoggers(d) {
+			shape, l := shape, l
+			b.Run("Fallback/"+shape.name+"/"+l.name, func(b *testing.B) {
+				defer reportScenario(b, d)()
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						l.fallback("user", shape.plain)
+					}
+				})
+			})
+			if !shape.marshaled {
+				continue
+			}
+			b.Run("Marshaler/"+shape.name+"/"+l.name, func(b *testing.B) {
+				defer reportScenario(b, d)()
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						l.marshaler("user", shape.array)
+					}
+				})
+			})
+		}
+	}
+}
+
+func TestMarshalJSONParity(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		marshaled, plain interface{}
+	}{
+		{"Object", _oneJSONUser, _onePlainUser},
+		{"Array", _tenJSONUsers, _tenPlainUsers},
+	} {
+		want, err := json.Marshal(tt.plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(tt.marshaled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: MarshalJSON wrote %s, encoding/json %s", tt.name, got, want)
+		}
+	}
+}
+
+func TestLogValueParity(t *testing.T) {
+	field := func(v interface{}) interface{} {
+		var out bytes.Buffer
+		slog.New(slog.NewJSONHandler(&out)).LogAttrs(slog.LevelInfo, getMessage(0), slog.Any("user", v))
+		var record map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out.String(), err)
+		}
+		return normalizeField(record["user"])
+	}
+	if got, want := field(_oneJSONUser), field(_onePlainUser); !reflect.DeepEqual(got, want) {
+		t.Errorf("LogValue wrote %v, encoding/json %v", got, want)
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{
+		"Jane Doe",
+		`quote " and backslash \`,
+		"new\nline, tab\t, nul \x00 and bell \a",
+		"<html> & co",
+		"ünïcödé, line \u2028 and paragraph \u2029 separators",
+		"invalid \xff utf-8",
+	} {
+		want, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := appendJSONString(nil, s)
+		var decoded, wantDecoded string
+		if err := json.Unmarshal(got, &decoded); err != nil {
+			t.Errorf("appendJSONString(%q) = %s, invalid JSON: %v", s, got, err)
+			continue
+		}
+		_ = json.Unmarshal(want, &wantDecoded)
+		if decoded != wantDecoded {
+			t.Errorf("appendJSONString(%q) = %s, encoding/json wrote %s", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+//go:build !windows && !plan9
+
+package benchmarks
+
+import (
+	"errors"
+	"io"
+	"log/syslog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	// Slow readers read once a millisecond, 64KB from TCP connections and a
+	// datagram otherwise, through 64KB socket buffers. These fill quickly
+	// enough for TCP and unix socket writers to block within a benchmark run,
+	// and for UDP to drop records.
+	slowReaderChunk = 64 << 10
+	slowReaderDelay = time.Millisecond
+)
+
+// drainServer is an in-process stand-in for a log shipping endpoint. It reads
+// and counts everything sent to it, optionally pausing after each read to
+// simulate a slow consumer.
+type drainServer struct {
+	// received comes first to be 64-bit aligned for the atomic operations on
+	// 32-bit platforms.
+	received int64
+	network  string
+	addr     string
+	chunk    int
+	delay    time.Duration
+	closer   io.Closer
+	wg       sync.WaitGroup
+}
+
+func startDrainServer(tb testing.TB, network string, chunk int, delay time.Duration) *drainServer {
+	s := &drainServer{network: network, chunk: chunk, delay: delay}
+
+	switch network {
+	case "tcp":
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			tb.Fatal(err)
+		}
+		s.addr, s.closer = ln.Addr().String(), ln
+		s.wg.Add(1)
+		go s.accept(ln)
+	case "udp":
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			tb.Fatal(err)
+		}
+		s.addr, s.closer = pc.LocalAddr().String(), pc
+		s.wg.Add(1)
+		go s.drainPackets(pc)
+	case "unixgram":
+		// Keep the path short, unix socket paths are limited to ~100 bytes.
+		dir, err := os.MkdirTemp("", "sink")
+		if err != nil {
+			tb.Fatal(err)
+		}
+		tb.Cleanup(func() { _ = os.RemoveAll(dir) })
+		s.addr = filepath.Join(dir, "log.sock")
+		pc, err := net.ListenPacket("unixgram", s.addr)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		s.closer = pc
+		s.wg.Add(1)
+		go s.drainPackets(pc)
+	default:
+		tb.Fatalf("unsupported network %q", network)
+	}
+
+	tb.Cleanup(func() {
+		_ = s.closer.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *drainServer) accept(ln net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		if s.delay > 0 {
+			_ = conn.(*net.TCPConn).SetReadBuffer(s.chunk)
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			buf := make([]byte, s.chunk)
+			for {
+				n, err := conn.Read(buf)
+				atomic.AddInt64(&s.received, int64(n))
+				if err != nil {
+					return
+				}
+				if s.delay > 0 {
+					time.Sleep(s.delay)
+				}
+			}
+		}()
+	}
+}
+
+func (s *drainServer) drainPackets(pc net.PacketConn) {
+	defer s.wg.Done()
+	// Give datagram sockets room, so losses come from the writers outpacing
+	// a real consumer rather than from the default receive buffer. Slow
+	// readers get chunk bytes instead, to fall behind within a run.
+	if s.delay > 0 {
+		_ = pc.(interface{ SetReadBuffer(int) error }).SetReadBuffer(s.chunk)
+	} else if uc, ok := pc.(*net.UDPConn); ok {
+		_ = uc.SetReadBuffer(4 << 20)
+	}
+	buf := make([]byte, 64<<10)
+	for {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		atomic.AddInt64(&s.received, int64(n))
+		if s.delay > 0 {
+			time.Sleep(s.delay)
+		}
+	}
+}
+
+// Received returns the number of bytes read so far. Datagrams carry no end of
+// stream, so it waits for the counter to settle before returning.
+func (s *drainServer) Received() int64 {
+	n := atomic.LoadInt64(&s.received)
+	for {
+		time.Sleep(20 * time.Millisecond)
+		m := atomic.LoadInt64(&s.received)
+		if m == n {
+			return n
+		}
+		n = m
+	}
+}
+
+// dial connects a client writer to the server. Unix datagram servers stand in
+// for the local syslog socket, so they are written to through log/syslog.
+func (s *drainServer) dial(tb testing.TB) io.WriteCloser {
+	if s.network == "unixgram" {
+		w, err := syslog.Dial("unixgram", s.addr, syslog.LOG_INFO|syslog.LOG_LOCAL0, "benchmarks")
+		if err != nil {
+			tb.Fatal(err)
+		}
+		return w
+	}
+
+	conn, err := net.Dial(s.network, s.addr)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if tc, ok := conn.(*net.TCPConn); ok && s.delay > 0 {
+		_ = tc.SetWriteBuffer(s.chunk)
+	}
+	return conn
+}
+
+var _networkSinks = []struct {
+	name    string
+	network string
+	chunk   int
+	delay   time.Duration
+}{
+	{"TCP", "tcp", 32 << 10, 0},
+	{"UDP", "udp", 0, 0},
+	{"UnixSyslog", "unixgram", 0, 0},
+	{"TCP.SlowReader", "tcp", slowReaderChunk, slowReaderDelay},
+	{"UDP.SlowReader", "udp", slowReaderChunk, slowReaderDelay},
+	{"UnixSyslog.SlowReader", "unixgram", slowReaderChunk, slowReaderDelay},
+}
+
+func BenchmarkNetworkSink(b *testing.B) {
+	b.Logf("Logging to loopback TCP, UDP and unix socket syslog endpoints.")
+	for _, sink := range _networkSinks {
+		sink := sink
+		for _, l := range writerLoggers() {
+			l := l
+			b.Run(sink.name+"/"+l.name, func(b *testing.B) {
+				defer reportScenario(b, nil)()
+				server := startDrainServer(b, sink.network, sink.chunk, sink.delay)
+				conn := server.dial(b)
+				log, stop := l.new(conn)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						log()
+					}
+				})
+				b.StopTimer()
+				stop()
+				_ = conn.Close()
+				b.ReportMetric(float64(server.Received())/float64(b.N), "recv-B/op")
+			})
+		}
+	}
+}
+
+func TestNetworkSinkReceives(t *testing.T) {
+	for _, sink := range _networkSinks {
+		sink := sink
+		for _, l := range writerLoggers() {
+			l := l
+			t.Run(sink.name+"/"+l.name, func(t *testing.T) {
+				server := startDrainServer(t, sink.network, sink.chunk, sink.delay)
+				conn := server.dial(t)
+				log, stop := l.new(conn)
+				for i := 0; i < 10; i++ {
+					log()
+				}
+				stop()
+				_ = conn.Close()
+				if n := server.Received(); n == 0 {
+					t.Errorf("%s received nothing", sink.name)
+				}
+			})
+		}
+	}
+}
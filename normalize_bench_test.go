@@ -0,0 +1,329 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apex/log"
+	kitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// The standard keys and time layout every normalised adapter writes. zerolog
+// only has global settings for its keys, so its defaults are the norm.
+const (
+	normalTimeKey    = "time"
+	normalLevelKey   = "level"
+	normalMessageKey = "message"
+	// normalTimeLayout is the layout of zapcore.ISO8601TimeEncoder.
+	normalTimeLayout = "2006-01-02T15:04:05.000Z0700"
+)
+
+func newNormalZap(w io.Writer) *zap.Logger {
+	ec := zap.NewProductionEncoderConfig()
+	ec.TimeKey, ec.LevelKey, ec.MessageKey = normalTimeKey, normalLevelKey, normalMessageKey
+	ec.EncodeDuration = zapcore.NanosDurationEncoder
+	ec.EncodeTime = zapcore.ISO8601TimeEncoder
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(ec), zapcore.AddSync(w), zap.DebugLevel))
+}
+
+// normalTimeHook adds the time in the normal layout, which zerolog.Timestamp
+// can only get from the global TimeFieldFormat.
+type normalTimeHook struct{}
+
+func (normalTimeHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	e.Str(normalTimeKey, time.Now().Format(normalTimeLayout))
+}
+
+func newNormalZerolog(w io.Writer) zerolog.Logger {
+	return zerolog.New(w).Hook(normalTimeHook{})
+}
+
+func newNormalSlog(w io.Writer) slog.Logger {
+	return slog.New(slog.HandlerOptions{ReplaceAttr: func(a slog.Attr) slog.Attr {
+		switch {
+		case a.Key == slog.TimeKey && a.Value.Kind() == slog.TimeKind:
+			return slog.String(normalTimeKey, a.Value.Time().Format(normalTimeLayout))
+		case a.Key == slog.LevelKey:
+			return slog.String(normalLevelKey, strings.ToLower(a.Value.String()))
+		case a.Key == slog.MessageKey:
+			a.Key = normalMessageKey
+		}
+		return a
+	}}.NewJSONHandler(w))
+}
+
+func newNormalLogrus(w io.Writer) *logrus.Logger {
+	logger := newLogrusTo(w)
+	logger.Formatter = &logrus.JSONFormatter{
+		TimestampFormat: normalTimeLayout,
+		FieldMap: logrus.FieldMap{
+			logrus.FieldKeyTime:  normalTimeKey,
+			logrus.FieldKeyLevel: normalLevelKey,
+			logrus.FieldKeyMsg:   normalMessageKey,
+		},
+	}
+	return logger
+}
+
+// normalApexHandler writes apex entries flat with the normal keys, where its
+// JSON handler nests the fields and names the time "timestamp".
+type normalApexHandler struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (h *normalApexHandler) HandleLog(e *log.Entry) error {
+	record := make(map[string]interface{}, len(e.Fields)+3)
+	for key, value := range e.Fields {
+		record[key] = value
+	}
+	record[normalTimeKey] = e.Timestamp.Format(normalTimeLayout)
+	record[normalLevelKey] = e.Level.String()
+	record[normalMessageKey] = e.Message
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.enc.Encode(record)
+}
+
+func newNormalApexLog(w io.Writer) *log.Logger {
+	return &log.Logger{
+		Handler: &normalApexHandler{enc: json.NewEncoder(w)},
+		Level:   log.DebugLevel,
+	}
+}
+
+// newNormalKitLog returns a go-kit logger at info level. go-kit has no
+// standard keys, so the message has to be logged under normalMessageKey.
+func newNormalKitLog(w io.Writer) kitlog.Logger {
+	return level.Info(kitlog.With(kitlog.NewJSONLogger(w), normalTimeKey, kitlog.TimestampFormat(time.Now, normalTimeLayout)))
+}
+
+// normalLog15Format is log15's JsonFormat with the normal keys and layout.
+func normalLog15Format() log15.Format {
+	return log15.FormatFunc(func(r *log15.Record) []byte {
+		record := make(map[string]interface{}, len(r.Ctx)/2+3)
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			record[fmt.Sprint(r.Ctx[i])] = r.Ctx[i+1]
+		}
+		record[normalTimeKey] = r.Time.Format(normalTimeLayout)
+		record[normalLevelKey] = r.Lvl.String()
+		record[normalMessageKey] = r.Msg
+		b, err := json.Marshal(record)
+		if err != nil {
+			b, _ = json.Marshal(map[string]string{normalMessageKey: r.Msg, "error": err.Error()})
+		}
+		return append(b, '\n')
+	})
+}
+
+func newNormalLog15(w io.Writer) log15.Logger {
+	logger := log15.New()
+	logger.SetHandler(log15.StreamHandler(w, normalLog15Format()))
+	return logger
+}
+
+// newNormalLogyConsole renames logy's time key and drops the logger name,
+// which none of the others write. The JSON time layout isn't configurable.
+func newNormalLogyConsole() *logy.ConsoleConfig {
+	console := newLogyJsonConsole()
+	console.Json.KeyOverrides = logy.KeyOverrides{"timestamp": normalTimeKey}
+	console.Json.ExcludedKeys = []string{"logger"}
+	return console
+}
+
+// normalLoggers returns for each library a function logging the same record
+// with the normal keys.
+func normalLoggers(w io.Writer) []struct {
+	name string
+	log  func()
+} {
+	logyLogger := logy.Get()
+	slogLogger := newNormalSlog(w)
+	zapLogger := newNormalZap(w)
+	zerologLogger := newNormalZerolog(w)
+	apexLogger := newNormalApexLog(w)
+	kitLogger := newNormalKitLog(w)
+	log15Logger := newNormalLog15(w)
+	logrusLogger := newNormalLogrus(w)
+
+	return []struct {
+		name string
+		log  func()
+	}{
+		{"Logy", func() {
+			ctx := logy.WithContextFields(context.Background())
+			ctx = logy.WithValue(ctx, "int", _tenInts[0])
+			ctx = logy.WithValue(ctx, "string", _tenStrings[0])
+			ctx = logy.WithValue(ctx, "strings", _tenStrings)
+			ctx = logy.WithValue(ctx, "user", _oneUser)
+			logyLogger.I(ctx, getMessage(0))
+		}},
+		{"exp/slog", func() {
+			slogLogger.LogAttrs(slog.LevelInfo, getMessage(0),
+				slog.Int("int", _tenInts[0]),
+				slog.String("string", _tenStrings[0]),
+				slog.Any("strings", _tenStrings),
+				slog.Any("user", _oneUser))
+		}},
+		{"Zap", func() {
+			zapLogger.Info(getMessage(0),
+				zap.Int("int", _tenInts[0]),
+				zap.String("string", _tenStrings[0]),
+				zap.Strings("strings", _tenStrings),
+				zap.Object("user", _oneUser))
+		}},
+		{"rs/zerolog", func() {
+			zerologLogger.Info().
+				Int("int", _tenInts[0]).
+				Str("string", _tenStrings[0]).
+				Strs("strings", _tenStrings).
+				Object("user", _oneUser).
+				Msg(getMessage(0))
+		}},
+		{"apex/log", func() {
+			apexLogger.WithFields(log.Fields{
+				"int":     _tenInts[0],
+				"string":  _tenStrings[0],
+				"strings": _tenStrings,
+				"user":    _oneUser,
+			}).Info(getMessage(0))
+		}},
+		// go-kit would write user's String, its plain copy is encoded as JSON.
+		{"go-kit/kit/log", func() {
+			_ = kitLogger.Log(normalMessageKey, getMessage(0), "int", _tenInts[0], "string", _tenStrings[0],
+				"strings", _tenStrings, "user", _onePlainUser)
+		}},
+		{"inconshreveable/log15", func() {
+			log15Logger.Info(getMessage(0), "int", _tenInts[0], "string", _tenStrings[0],
+				"strings", _tenStrings, "user", _oneUser)
+		}},
+		{"sirupsen/logrus", func() {
+			logrusLogger.WithFields(logrus.Fields{
+				"int":     _tenInts[0],
+				"string":  _tenStrings[0],
+				"strings": _tenStrings,
+				"user":    _oneUser,
+			}).Info(getMessage(0))
+		}},
+	}
+}
+
+func BenchmarkNormalized(b *testing.B) {
+	b.Logf("Logging the same record with identical standard keys and time layout in every library.")
+	d := &Discarder{}
+	defer loadLogyIntoDevNull(b, &logy.Config{Level: logy.LevelDebug, Console: newNormalLogyConsole()})()
+
+	for _, l := range normalLoggers(d) {
+		l := l
+		b.Run(l.name, func(b *testing.B) {
+			defer reportScenario(b, d)()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					l.log()
+				}
+			})
+		})
+	}
+}
+
+// _normalLengthTolerance is how many bytes a record may be off the median
+// length once normalised. What is left are the libraries' own encodings of the
+// user fixture, e.g. zap's createdAt against the created_at of encoding/json
+// and its time with milliseconds.
+const _normalLengthTolerance = 4
+
+// canonicalRecord returns the fields of a normalised record, that is all but
+// the time and level, encoded the same way whichever library wrote them.
+func canonicalRecord(record map[string]interface{}) string {
+	fields := make(map[string]interface{}, len(record))
+	for key, value := range record {
+		if key != normalTimeKey && key != normalLevelKey {
+			fields[key] = value
+		}
+	}
+	b, _ := json.Marshal(normalizeField(fields))
+	return string(b)
+}
+
+func TestNormalizedRecords(t *testing.T) {
+	lengths := make(map[string]int)
+	canonical := make(map[string]string)
+	w := &switchWriter{}
+	for _, l := range normalLoggers(w) {
+		var out bytes.Buffer
+		w.Writer = &out
+		if l.name == "Logy" {
+			restore := redirectLogy(&out, &logy.Config{Level: logy.LevelDebug, Console: newNormalLogyConsole()})
+			l.log()
+			restore()
+		} else {
+			l.log()
+		}
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+			t.Fatalf("%s wrote invalid JSON %q: %v", l.name, out.String(), err)
+		}
+		if record[normalMessageKey] != getMessage(0) {
+			t.Errorf("%s: %q is %v, want the message: %s", l.name, normalMessageKey, record[normalMessageKey], out.String())
+		}
+		if _, ok := record["int"]; !ok {
+			t.Errorf("%s: the record doesn't have the fields: %s", l.name, out.String())
+		}
+		canonical[l.name] = canonicalRecord(record)
+
+		ts, ok := record[normalTimeKey].(string)
+		if !ok {
+			t.Errorf("%s: no %q: %s", l.name, normalTimeKey, out.String())
+		}
+		// logy's time layout and level names aren't configurable, so only its
+		// keys and fields are normalised, see the README.
+		if l.name == "Logy" {
+			continue
+		}
+		if _, err := time.Parse(normalTimeLayout, ts); ok && err != nil {
+			t.Errorf("%s: time %q isn't ISO8601: %v", l.name, ts, err)
+		}
+		if lvl := fmt.Sprint(record[normalLevelKey]); lvl != "info" {
+			t.Errorf("%s: %q is %q, want info", l.name, normalLevelKey, lvl)
+		}
+		lengths[l.name] = out.Len()
+	}
+
+	for name, fields := range canonical {
+		if want := canonical["exp/slog"]; fields != want {
+			t.Errorf("%s wrote the fields %s, exp/slog %s", name, fields, want)
+		}
+	}
+
+	sorted := make([]int, 0, len(lengths))
+	for _, n := range lengths {
+		sorted = append(sorted, n)
+	}
+	sort.Ints(sorted)
+	median := sorted[len(sorted)/2]
+	for name, n := range lengths {
+		if n-median > _normalLengthTolerance || median-n > _normalLengthTolerance {
+			t.Errorf("%s wrote %d bytes, %+d off the median of %d", name, n, n-median, median)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package benchmarks
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/pprof"
+	"testing"
+)
+
+var _benchProfiles = flag.String("benchprofile", "", "write the CPU profile of every sub-benchmark into `dir`, see cmd/benchprof")
+
+// startCPUProfile profiles the benchmark into the -benchprofile directory
+// until the returned function is called. The profile is named after the
+// benchmark, so every run of a sub-benchmark overwrites the previous one and
+// the longest run is kept.
+func startCPUProfile(b *testing.B) func() {
+	if *_benchProfiles == "" {
+		return func() {}
+	}
+	if err := os.MkdirAll(*_benchProfiles, 0o755); err != nil {
+		b.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(*_benchProfiles, profileName(b)+".cpu.pprof"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		// Most likely the package is profiled as a whole with -cpuprofile.
+		_ = f.Close()
+		b.Fatal(err)
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		if err := f.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestBenchProfile runs a sub-benchmark of BenchmarkWithContext with
+// -benchprofile in a test binary of its own, the way cmd/benchprof documents,
+// and checks its profile was written where benchprof looks for it.
+func TestBenchProfile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the test binary again")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^$", "-test.bench=^BenchmarkWithContext$/^Zap$", "-test.benchtime=1x", "-benchprofile="+dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("running the benchmark: %v\n%s", err, out)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "BenchmarkWithContext-Zap.cpu.pprof"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() == 0 {
+		t.Error("the profile is empty")
+	}
+}
@@ -0,0 +1,277 @@
+package benchmarks
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	rateLimitBurst  = 10
+	rateLimitPeriod = 100 * time.Millisecond
+	// rateLimitThereafter mirrors the "thereafter" setting of newSampledLogger
+	// for the libraries that ship a sampler.
+	rateLimitThereafter = 100
+)
+
+// tokenBucketWriter lets through at most burst lines per period and discards
+// the rest, counting both. It is used for the libraries that have no sampler
+// of their own. Lines are counted by their trailing newline, so records split
+// across several writes are still treated as a single record.
+type tokenBucketWriter struct {
+	Discarder
+	mu         sync.Mutex
+	burst      float64
+	rate       float64
+	tokens     float64
+	last       time.Time
+	inLine     bool
+	allowLine  bool
+	written    int64
+	suppressed int64
+}
+
+func newTokenBucketWriter(burst int, period time.Duration) *tokenBucketWriter {
+	return &tokenBucketWriter{
+		burst:  float64(burst),
+		rate:   float64(burst) / float64(period),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (w *tokenBucketWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(b)
+	for len(b) > 0 {
+		line := b
+		end := false
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line = b[:i+1]
+			end = true
+		}
+		b = b[len(line):]
+
+		if !w.inLine {
+			w.allowLine = w.take()
+			if w.allowLine {
+				w.written++
+			} else {
+				w.suppressed++
+			}
+		}
+		w.inLine = !end
+
+		if w.allowLine {
+			_, _ = w.Discarder.Write(line)
+		}
+	}
+	return n, nil
+}
+
+func (w *tokenBucketWriter) take() bool {
+	now := time.Now()
+	w.tokens += float64(now.Sub(w.last)) * w.rate
+	if w.tokens > w.burst {
+		w.tokens = w.burst
+	}
+	w.last = now
+
+	if w.tokens < 1 {
+		return false
+	}
+	w.tokens--
+	return true
+}
+
+func (w *tokenBucketWriter) Suppressed() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.suppressed
+}
+
+// countingSampler counts the events its underlying zerolog sampler rejects.
+type countingSampler struct {
+	zerolog.Sampler
+	dropped int64
+}
+
+func (s *countingSampler) Sample(lvl zerolog.Level) bool {
+	if s.Sampler.Sample(lvl) {
+		return true
+	}
+	atomic.AddInt64(&s.dropped, 1)
+	return false
+}
+
+type rateLimitedLogger struct {
+	name string
+	// written is the number of records expected to be written when
+	// rateLimitBurst+990 identical records are logged within one period.
+	written int64
+	build   func(burst int, period time.Duration) (log func(), suppressed func() int64, stop func())
+}
+
+func rateLimitedLoggers() []rateLimitedLogger {
+	return []rateLimitedLogger{
+		{
+			name:    "Logy.Pipe",
+			written: rateLimitBurst,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				w := newTokenBucketWriter(burst, period)
+				stop := redirectLogy(w, &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: newLogyJsonConsole()})
+				logger := logy.Get()
+				ctx := fakeLogyContext()
+				return func() {
+					logger.I(ctx, getMessage(0))
+				}, w.Suppressed, stop
+			},
+		},
+		{
+			name:    "exp/slog",
+			written: rateLimitBurst,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				w := newTokenBucketWriter(burst, period)
+				logger := slog.New(slog.NewJSONHandler(w)).With(fakeSugarFields()...)
+				return func() {
+					logger.Info(getMessage(0))
+				}, w.Suppressed, func() {}
+			},
+		},
+		{
+			name:    "Zap",
+			written: rateLimitBurst + 9,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				var dropped int64
+				core := zapcore.NewSamplerWithOptions(
+					newZapLogger(zap.DebugLevel).Core(),
+					period,
+					burst,
+					rateLimitThereafter,
+					zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+						if dec&zapcore.LogDropped > 0 {
+							atomic.AddInt64(&dropped, 1)
+						}
+					}),
+				)
+				logger := zap.New(core).With(fakeFields()...)
+				suppressed := func() int64 {
+					return atomic.LoadInt64(&dropped)
+				}
+				return func() {
+					logger.Info(getMessage(0))
+				}, suppressed, func() {}
+			},
+		},
+		{
+			name:    "rs/zerolog",
+			written: rateLimitBurst + 10,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				sampler := &countingSampler{Sampler: &zerolog.BurstSampler{
+					Burst:       uint32(burst),
+					Period:      period,
+					NextSampler: &zerolog.BasicSampler{N: rateLimitThereafter},
+				}}
+				logger := fakeZerologContext(newZerolog().With()).Logger().Sample(sampler)
+				suppressed := func() int64 {
+					return atomic.LoadInt64(&sampler.dropped)
+				}
+				return func() {
+					logger.Info().Msg(getMessage(0))
+				}, suppressed, func() {}
+			},
+		},
+		{
+			name:    "apex/log",
+			written: rateLimitBurst,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				w := newTokenBucketWriter(burst, period)
+				logger := newApexLogTo(w).WithFields(fakeApexFields())
+				return func() {
+					logger.Info(getMessage(0))
+				}, w.Suppressed, func() {}
+			},
+		},
+		{
+			name:    "go-kit/kit/log",
+			written: rateLimitBurst,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				w := newTokenBucketWriter(burst, period)
+				logger := newKitLogTo(w, fakeSugarFields()...)
+				return func() {
+					_ = logger.Log("msg", getMessage(0))
+				}, w.Suppressed, func() {}
+			},
+		},
+		{
+			name:    "inconshreveable/log15",
+			written: rateLimitBurst,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				w := newTokenBucketWriter(burst, period)
+				logger := newLog15To(w).New(fakeSugarFields()...)
+				return func() {
+					logger.Info(getMessage(0))
+				}, w.Suppressed, func() {}
+			},
+		},
+		{
+			name:    "sirupsen/logrus",
+			written: rateLimitBurst,
+			build: func(burst int, period time.Duration) (func(), func() int64, func()) {
+				w := newTokenBucketWriter(burst, period)
+				logger := newLogrusTo(w).WithFields(fakeLogrusFields())
+				return func() {
+					logger.Info(getMessage(0))
+				}, w.Suppressed, func() {}
+			},
+		},
+	}
+}
+
+func BenchmarkRateLimited(b *testing.B) {
+	b.Logf("Logging the same message and fields in a burst, with rate limiting or sampling enabled.")
+	for _, l := range rateLimitedLoggers() {
+		l := l
+		b.Run(l.name, func(b *testing.B) {
+			defer reportScenario(b, nil)()
+			log, suppressed, stop := l.build(rateLimitBurst, rateLimitPeriod)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					log()
+				}
+			})
+			b.StopTimer()
+			stop()
+			b.ReportMetric(float64(suppressed())/float64(b.N), "suppressed/op")
+		})
+	}
+}
+
+func TestRateLimitedCounts(t *testing.T) {
+	const records = rateLimitBurst + 990
+	for _, l := range rateLimitedLoggers() {
+		l := l
+		t.Run(l.name, func(t *testing.T) {
+			log, suppressed, stop := l.build(rateLimitBurst, time.Hour)
+			for i := 0; i < records; i++ {
+				log()
+			}
+			stop()
+
+			if got, want := suppressed(), records-l.written; got != want {
+				t.Errorf("suppressed %d records, want %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/procyon-projects/logy"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	redacted      = "[REDACTED]"
+	_secretToken  = "tok_4f9a1c2e8b7d"
+	_contactNote  = "reach me at jane@test.com"
+	_redactedJSON = `"$1":"` + redacted + `"`
+)
+
+var (
+	_redactedKeys = map[string]bool{"email": true, "token": true}
+	_emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// _redactedKeyPattern matches the string values of the redacted keys in
+	// encoded JSON.
+	_redactedKeyPattern = regexp.MustCompile(`"(email|token)":"(?:[^"\\]|\\.)*"`)
+)
+
+// redactString masks value when its key is redacted, and the emails in it
+// otherwise.
+func redactString(key, value string) string {
+	if _redactedKeys[key] {
+		return redacted
+	}
+	if !_emailPattern.MatchString(value) {
+		return value
+	}
+	return _emailPattern.ReplaceAllLiteralString(value, redacted)
+}
+
+// redactJSON masks an encoded record, for the libraries which can only be
+// hooked after encoding.
+func redactJSON(b []byte) []byte {
+	b = _redactedKeyPattern.ReplaceAll(b, []byte(_redactedJSON))
+	return _emailPattern.ReplaceAllLiteral(b, []byte(redacted))
+}
+
+// redactWriter masks the records written through it.
+type redactWriter struct {
+	io.Writer
+}
+
+func (w redactWriter) Write(p []byte) (int, error) {
+	if _, err := w.Writer.Write(redactJSON(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// redactFormatter masks the records formatted by logrus.
+type redactFormatter struct {
+	logrus.Formatter
+}
+
+func (f redactFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	b, err := f.Formatter.Format(e)
+	if err != nil {
+		return nil, err
+	}
+	return redactJSON(b), nil
+}
+
+// redactCore masks the fields of zap records, reaching into object and array
+// marshalers through their encoders.
+type redactCore struct {
+	zapcore.Core
+}
+
+func (c redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactCore{c.Core.With(redactFields(fields))}
+}
+
+func (c redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redactedFields := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		switch {
+		case _redactedKeys[f.Key]:
+			f = zap.String(f.Key, redacted)
+		case f.Type == zapcore.StringType:
+			f.String = redactString(f.Key, f.String)
+		case f.Type == zapcore.ObjectMarshalerType:
+			f.Interface = redactObject{f.Interface.(zapcore.ObjectMarshaler)}
+		case f.Type == zapcore.ArrayMarshalerType:
+			f.Interface = redactArray{f.Interface.(zapcore.ArrayMarshaler)}
+		}
+		redactedFields[i] = f
+	}
+	return redactedFields
+}
+
+type redactObject struct {
+	zapcore.ObjectMarshaler
+}
+
+func (o redactObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(redactObjectEncoder{enc})
+}
+
+type redactArray struct {
+	zapcore.ArrayMarshaler
+}
+
+func (a redactArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(redactArrayEncoder{enc})
+}
+
+type redactObjectEncoder struct {
+	zapcore.ObjectEncoder
+}
+
+func (enc redactObjectEncoder) AddString(key, value string) {
+	enc.ObjectEncoder.AddString(key, redactString(key, value))
+}
+
+func (enc redactObjectEncoder) AddObject(key string, m zapcore.ObjectMarshaler) error {
+	return enc.ObjectEncoder.AddObject(key, redactObject{m})
+}
+
+func (enc redactObjectEncoder) AddArray(key string, m zapcore.ArrayMarshaler) error {
+	return enc.ObjectEncoder.AddArray(key, redactArray{m})
+}
+
+type redactArrayEncoder struct {
+	zapcore.ArrayEncoder
+}
+
+func (enc redactArrayEncoder) AppendString(value string) {
+	enc.ArrayEncoder.AppendString(redactString("", value))
+}
+
+func (enc redactArrayEncoder) AppendObject(m zapcore.ObjectMarshaler) error {
+	return enc.ArrayEncoder.AppendObject(redactObject{m})
+}
+
+func (enc redactArrayEncoder) AppendArray(m zapcore.ArrayMarshaler) error {
+	return enc.ArrayEncoder.AppendArray(redactArray{m})
+}
+
+// redactAttr is a slog ReplaceAttr masking strings, and values slog can't see
+// into by encoding them first.
+func redactAttr(a slog.Attr) slog.Attr {
+	if _redactedKeys[a.Key] {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.StringKind:
+		if s := a.Value.String(); _emailPattern.MatchString(s) {
+			return slog.String(a.Key, redactString(a.Key, s))
+		}
+	case slog.AnyKind:
+		b, err := json.Marshal(a.Value.Any())
+		if err != nil {
+			return a
+		}
+		return slog.Any(a.Key, json.RawMessage(redactJSON(b)))
+	}
+	return a
+}
+
+// logy has no hook to mask records with, so values are wrapped with
+// redacting marshalers where they are added to the context.
+type redactLogyObject struct {
+	logy.ObjectMarshaler
+}
+
+func (o redactLogyObject) MarshalObject(encoder logy.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalObject(redactLogyEncoder{encoder})
+}
+
+type redactLogyArray struct {
+	logy.ArrayMarshaler
+}
+
+func (a redactLogyArray) MarshalArray(encoder logy.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalArray(redactLogyArrayEncoder{encoder})
+}
+
+type redactLogyEncoder struct {
+	logy.ObjectEncoder
+}
+
+func (enc redactLogyEncoder) AddString(key, value string) {
+	enc.ObjectEncoder.AddString(key, redactString(key, value))
+}
+
+func (enc redactLogyEncoder) AddObject(key string, m logy.ObjectMarshaler) error {
+	return enc.ObjectEncoder.AddObject(key, redactLogyObject{m})
+}
+
+type redactLogyArrayEncoder struct {
+	logy.ArrayEncoder
+}
+
+func (enc redactLogyArrayEncoder) AppendObject(m logy.ObjectMarshaler) error {
+	return enc.ArrayEncoder.AppendObject(redactLogyObject{m})
+}
+
+func redactLogyValue(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return redactString(key, v)
+	case logy.ObjectMarshaler:
+		return redactLogyObject{v}
+	case logy.ArrayMarshaler:
+		return redactLogyArray{v}
+	}
+	return v
+}
+
+// redactionLoggers returns for each library a function logging a record with
+// the user fixtures, a token and an email in free text, masking them when
+// redact is set.
+func redactionLoggers(w io.Writer, redact bool) []struct {
+	name string
+	log  func()
+} {
+	encoded := w
+	if redact {
+		encoded = redactWriter{w}
+	}
+
+	logyLogger := logy.Get()
+	logyValue := func(key string, v interface{}) interface{} { return v }
+	if redact {
+		logyValue = redactLogyValue
+	}
+
+	var opts slog.HandlerOptions
+	if redact {
+		opts.ReplaceAttr = redactAttr
+	}
+	slogLogger := slog.New(opts.NewJSONHandler(w))
+
+	zapLogger := newZapLoggerTo(w, zap.DebugLevel)
+	if redact {
+		zapLogger = zapLogger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core { return redactCore{c} }))
+	}
+
+	logrusLogger := newLogrusTo(w)
+	if redact {
+		logrusLogger.Formatter = redactFormatter{logrusLogger.Formatter}
+	}
+
+	zerologLogger := newZerologTo(encoded)
+	apexLogger := newApexLogTo(encoded)
+	kitLogger := newKitLogTo(encoded)
+	log15Logger := newLog15To(encoded)
+
+	// logy has no masking of its own, its values are redacted by the caller
+	// before they are added to the context.
+	logyName := "Logy"
+	if redact {
+		logyName = "Logy.CallerSide"
+	}
+
+	return []struct {
+		name string
+		log  func()
+	}{
+		{logyName, func() {
+			ctx := logy.WithContextFields(context.Background())
+			ctx = logy.WithValue(ctx, "user", logyValue("user", _oneUser))
+			ctx = logy.WithValue(ctx, "users", logyValue("users", _tenUsers))
+			ctx = logy.WithValue(ctx, "token", logyValue("token", _secretToken))
+			ctx = logy.WithValue(ctx, "note", logyValue("note", _contactNote))
+			logyLogger.I(ctx, getMessage(0))
+		}},
+		{"exp/slog", func() {
+			slogLogger.LogAttrs(slog.LevelInfo, getMessage(0),
+				slog.Any("user", _oneUser),
+				slog.Any("users", _tenUsers),
+				slog.String("token", _secretToken),
+				slog.String("note", _contactNote))
+		}},
+		{"Zap", func() {
+			zapLogger.Info(getMessage(0),
+				zap.Object("user", _oneUser),
+				zap.Array("users", _tenUsers),
+				zap.String("token", _secretToken),
+				zap.String("note", _contactNote))
+		}},
+		{"rs/zerolog", func() {
+			zerologLogger.Info().
+				Object("user", _oneUser).
+				Array("users", _tenUsers).
+				Str("token", _secretToken).
+				Str("note", _contactNote).
+				Msg(getMessage(0))
+		}},
+		{"apex/log", func() {
+			apexLogger.WithFields(log.Fields{
+				"user":  _oneUser,
+				"users": _tenUsers,
+				"token": _secretToken,
+				"note":  _contactNote,
+			}).Info(getMessage(0))
+		}},
+		{"go-kit/kit/log", func() {
+			_ = kitLogger.Log("msg", getMessage(0), "user", _oneUser, "users", _tenUsers,
+				"token", _secretToken, "note", _contactNote)
+		}},
+		{"inconshreveable/log15", func() {
+			log15Logger.Info(getMessage(0), "user", _oneUser, "users", _tenUsers,
+				"token", _secretToken, "note", _contactNote)
+		}},
+		{"sirupsen/logrus", func() {
+			logrusLogger.WithFields(logrus.Fields{
+				"user":  _oneUser,
+				"users": _tenUsers,
+				"token": _secretToken,
+				"note":  _contactNote,
+			}).Info(getMessage(0))
+		}},
+	}
+}
+
+func BenchmarkRedaction(b *testing.B) {
+	b.Logf("Logging the user fixtures, a token and an email, with and without masking them.")
+	d := &Discarder{}
+	defer loadLogyIntoDevNull(b, &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: newLogyJsonConsole()})()
+
+	for _, mode := range []struct {
+		name   string
+		redact bool
+	}{{"Plain", false}, {"Redacted", true}} {
+		for _, l := range redactionLoggers(d, mode.redact) {
+			l := l
+			b.Run(mode.name+"/"+l.name, func(b *testing.B) {
+				defer reportScenario(b, d)()
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						l.log()
+					}
+				})
+			})
+		}
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	w := &switchWriter{}
+	for _, l := range redactionLoggers(w, true) {
+		var out bytes.Buffer
+		w.Writer = &out
+		if l.name == "Logy.CallerSide" {
+			restore := redirectLogy(&out, &logy.Config{Level: logy.LevelDebug, Console: newLogyJsonConsole()})
+			l.log()
+			restore()
+		} else {
+			l.log()
+		}
+
+		record := out.String()
+		if record == "" {
+			t.Errorf("%s wrote nothing", l.name)
+		}
+		for _, secret := range []string{_oneUser.Email, _secretToken} {
+			if strings.Contains(record, secret) {
+				t.Errorf("%s leaked %q: %s", l.name, secret, record)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package benchmarks
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/procyon-projects/logy"
+)
+
+// plainUser is user without any marshaler, so every library has to fall back
+// to reflection or encoding/json for it.
+type plainUser struct {
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	_onePlainUser = &plainUser{
+		Name:      _oneUser.Name,
+		Email:     _oneUser.Email,
+		CreatedAt: _oneUser.CreatedAt,
+	}
+	_tenPlainUsers = []*plainUser{
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+		_onePlainUser,
+	}
+	_userMap = map[string]interface{}{
+		"name":       _oneUser.Name,
+		"email":      _oneUser.Email,
+		"created_at": _oneUser.CreatedAt,
+	}
+)
+
+// fallbackShape is a value without any marshaler, with the fields of the user
+// fixtures. marshaled tells whether it is benchmarked next to them logged
+// through the libraries' marshalers, the ten users if array: the map has no
+// marshaled counterpart of its own.
+type fallbackShape struct {
+	name      string
+	plain     interface{}
+	marshaled bool
+	array     bool
+}
+
+var _fallbackShapes = []fallbackShape{
+	{name: "Struct", plain: _onePlainUser, marshaled: true},
+	{name: "Map", plain: _userMap},
+	{name: "Slice", plain: _tenPlainUsers, marshaled: true, array: true},
+}
+
+// loggedField returns the normalised value of key in the JSON record log wrote
+// with the logger of library name, built on w, and false if the record doesn't
+// have it. apex/log's fields are looked up under its "fields" key.
+func loggedField(t *testing.T, w *switchWriter, name, key string, log func()) (interface{}, bool) {
+	var out bytes.Buffer
+	w.Writer = &out
+	if name == "Logy" {
+		restore := redirectLogy(&out, &logy.Config{Level: logy.LevelDebug, Console: newLogyJsonConsole()})
+		log()
+		restore()
+	} else {
+		log()
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(out.Bytes()), &record); err != nil {
+		t.Fatalf("%s wrote invalid JSON %q: %v", name, out.String(), err)
+	}
+	if fields, ok := record["fields"].(map[string]interface{}); ok {
+		record = fields
+	}
+	v, ok := record[key]
+	return normalizeField(v), ok
+}
+
+// normalizeField makes the output of marshalers and of encoding/json
+// comparable: keys are compared without case and underscores, and times
+// whatever their layout.
+func normalizeField(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[strings.ToLower(strings.ReplaceAll(key, "_", ""))] = normalizeField(value)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i := range v {
+			s[i] = normalizeField(v[i])
+		}
+		return s
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return v
+}
+
+func TestReflectionFallbackEquivalence(t *testing.T) {
+	w := &switchWriter{}
+	for _, shape := range _fallbackShapes {
+		for _, l := range marshalerLoggers(w) {
+			l, shape := l, shape
+			t.Run(shape.name+"/"+l.name, func(t *testing.T) {
+				if l.flattens {
+					t.Skipf("%s's marshaler writes the fields of the users under keys of their own", l.name)
+				}
+				marshaled, marshaledOk := loggedField(t, w, l.name, "user", func() { l.marshaler("user", shape.array) })
+				fallback, fallbackOk := loggedField(t, w, l.name, "user", func() { l.fallback("user", shape.plain) })
+				if !marshaledOk || !fallbackOk {
+					t.Fatalf("the records don't have the field")
+				}
+				if !reflect.DeepEqual(marshaled, fallback) {
+					t.Errorf("marshaler wrote %v, fallback wrote %v", marshaled, fallback)
+				}
+			})
+		}
+	}
+}
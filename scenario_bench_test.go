@@ -1,39 +1,160 @@
 package benchmarks
 
 import (
+	"bytes"
 	"context"
 	"github.com/procyon-projects/logy"
 	"golang.org/x/exp/slices"
 	"golang.org/x/exp/slog"
-	"io"
 	"log"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"go.uber.org/zap"
 )
 
+// Syncer counts Sync calls and the errors it returned. It is safe for
+// concurrent use, the error set with SetError must be set before logging.
 type Syncer struct {
+	syncs  int64
+	errors int64
 	err    error
-	called bool
 }
 
+// SetError makes every following Sync fail with err, and every write too when
+// the Syncer is part of a Discarder.
 func (s *Syncer) SetError(err error) {
 	s.err = err
 }
 
 func (s *Syncer) Sync() error {
-	s.called = true
+	atomic.AddInt64(&s.syncs, 1)
+	if s.err != nil {
+		atomic.AddInt64(&s.errors, 1)
+	}
 	return s.err
 }
 
 func (s *Syncer) Called() bool {
-	return s.called
+	return s.Syncs() > 0
 }
 
-type Discarder struct{ Syncer }
+func (s *Syncer) Syncs() int64 {
+	return atomic.LoadInt64(&s.syncs)
+}
+
+// Errors returns how many calls failed, whether to Sync or to Write.
+func (s *Syncer) Errors() int64 {
+	return atomic.LoadInt64(&s.errors)
+}
+
+// Discarder throws away everything written to it, keeping count of the writes
+// and bytes it was handed.
+type Discarder struct {
+	writes int64
+	bytes  int64
+	Syncer
+}
 
 func (d *Discarder) Write(b []byte) (int, error) {
-	return io.Discard.Write(b)
+	atomic.AddInt64(&d.writes, 1)
+	if d.err != nil {
+		atomic.AddInt64(&d.errors, 1)
+		return 0, d.err
+	}
+	atomic.AddInt64(&d.bytes, int64(len(b)))
+	return len(b), nil
+}
+
+func (d *Discarder) Writes() int64 {
+	return atomic.LoadInt64(&d.writes)
+}
+
+func (d *Discarder) Bytes() int64 {
+	return atomic.LoadInt64(&d.bytes)
+}
+
+// reportWrites reports the writes and bytes d received per record from the
+// call until the returned function runs, as in defer reportWrites(b, d)().
+// Nothing is reported if d is nil or no write reached it, logy's discard
+// target never hands its records to a writer.
+func reportWrites(b *testing.B, d *Discarder) func() {
+	if d == nil {
+		return func() {}
+	}
+	writes, n := d.Writes(), d.Bytes()
+	return func() {
+		writes, n = d.Writes()-writes, d.Bytes()-n
+		if writes == 0 {
+			return
+		}
+		b.ReportMetric(float64(n)/float64(b.N), "bytes/op")
+		b.ReportMetric(float64(writes)/float64(b.N), "writes/op")
+	}
+}
+
+// reportScenario reports the writes into d, if not nil, and the GC pressure of
+// a scenario, see reportWrites and reportGC, and profiles it when -benchprofile
+// is set.
+func reportScenario(b *testing.B, d *Discarder) func() {
+	writes, gc := reportWrites(b, d), reportGC(b)
+	cpu := startCPUProfile(b)
+	return func() {
+		cpu()
+		gc()
+		writes()
+	}
+}
+
+func TestDiscarderAccounting(t *testing.T) {
+	const goroutines, records = 8, 1000
+	d := &Discarder{}
+	logger := newZapLoggerTo(d, zap.DebugLevel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < records; j++ {
+				logger.Info(getMessage(0))
+			}
+			_ = logger.Sync()
+		}()
+	}
+	wg.Wait()
+
+	if got := d.Writes(); got != goroutines*records {
+		t.Errorf("got %d writes, want %d", got, goroutines*records)
+	}
+	if d.Bytes() == 0 {
+		t.Error("got no bytes written")
+	}
+	if got := d.Syncs(); got != goroutines {
+		t.Errorf("got %d syncs, want %d", got, goroutines)
+	}
+
+	d.SetError(errExample)
+	logger.Info(getMessage(0))
+	if got := d.Errors(); got != 1 {
+		t.Errorf("got %d errors, want 1", got)
+	}
+}
+
+// lineCounter is a Discarder counting the lines written to it.
+type lineCounter struct {
+	Discarder
+	lines int64
+}
+
+func (c *lineCounter) Write(b []byte) (int, error) {
+	atomic.AddInt64(&c.lines, int64(bytes.Count(b, []byte{'\n'})))
+	return c.Discarder.Write(b)
+}
+
+func (c *lineCounter) Lines() int64 {
+	return atomic.LoadInt64(&c.lines)
 }
 
 type discardHandler struct {
@@ -69,6 +190,7 @@ func concat[T any](s1, s2 []T) []T {
 func BenchmarkDisabledWithoutFields(b *testing.B) {
 	b.Logf("Logging at a disabled level without any structured context.")
 	b.Run("Logy", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard}})
 
@@ -80,6 +202,7 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Logy.Formatting", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelError, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard}})
 
@@ -91,6 +214,7 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("exp/slog", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := slog.New(discardHandler{disabled: true})
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
@@ -100,7 +224,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -109,7 +235,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -120,7 +248,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -129,7 +259,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.SugarFormatting", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -138,7 +270,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("apex/log", func(b *testing.B) {
-		logger := newDisabledApexLog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledApexLog(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -147,7 +281,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("sirupsen/logrus", func(b *testing.B) {
-		logger := newDisabledLogrus()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledLogrus(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -156,7 +292,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := newDisabledZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledZerolog(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -165,7 +303,9 @@ func BenchmarkDisabledWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Formatting", func(b *testing.B) {
-		logger := newDisabledZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledZerolog(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -180,6 +320,7 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 	ctx := logy.WithContextFields(context.Background())
 
 	b.Run("Logy", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelError, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard}})
 
@@ -191,6 +332,7 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("Logy.Formatting", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelError, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard}})
 
@@ -202,6 +344,7 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("exp/slog", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := slog.New(discardHandler{disabled: true}).With(fakeFmtArgs()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
@@ -211,7 +354,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).With(fakeFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -220,7 +365,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).With(fakeFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -231,7 +378,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).With(fakeFields()...).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).With(fakeFields()...).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -240,7 +389,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.SugarFormatting", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).With(fakeFields()...).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).With(fakeFields()...).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -249,7 +400,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("apex/log", func(b *testing.B) {
-		logger := newDisabledApexLog().WithFields(fakeApexFields())
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledApexLog(d).WithFields(fakeApexFields())
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -258,7 +411,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("sirupsen/logrus", func(b *testing.B) {
-		logger := newDisabledLogrus().WithFields(fakeLogrusFields())
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledLogrus(d).WithFields(fakeLogrusFields())
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -267,7 +422,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := fakeZerologContext(newDisabledZerolog().With()).Logger()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := fakeZerologContext(newDisabledZerolog(d).With()).Logger()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -276,7 +433,9 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Formatting", func(b *testing.B) {
-		logger := newDisabledZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledZerolog(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -289,6 +448,7 @@ func BenchmarkDisabledAccumulatedContext(b *testing.B) {
 func BenchmarkDisabledAddingFields(b *testing.B) {
 	b.Logf("Logging at a disabled level, adding context at each log site.")
 	b.Run("Logy", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Named("")
 		logger.SetLevel(logy.LevelError)
 
@@ -300,7 +460,9 @@ func BenchmarkDisabledAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -309,7 +471,9 @@ func BenchmarkDisabledAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -320,7 +484,9 @@ func BenchmarkDisabledAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.ErrorLevel).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.ErrorLevel).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -329,7 +495,9 @@ func BenchmarkDisabledAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("apex/log", func(b *testing.B) {
-		logger := newDisabledApexLog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledApexLog(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -338,7 +506,9 @@ func BenchmarkDisabledAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("sirupsen/logrus", func(b *testing.B) {
-		logger := newDisabledLogrus()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledLogrus(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -347,7 +517,9 @@ func BenchmarkDisabledAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := newDisabledZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newDisabledZerolog(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -365,7 +537,7 @@ func BenchmarkWithoutFields(b *testing.B) {
 
 	b.Logf("Logging without any structured context.")
 	b.Run("Logy", func(b *testing.B) {
-
+		defer reportScenario(b, nil)()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -374,6 +546,7 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Logy.Formatting", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -382,6 +555,7 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("exp/slog", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := slog.New(discardHandler{disabled: false})
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
@@ -391,7 +565,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -400,7 +576,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -411,7 +589,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.CheckSampled", func(b *testing.B) {
-		logger := newSampledLogger(zap.DebugLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newSampledLoggerTo(d, zap.DebugLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			i := 0
@@ -424,7 +604,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -433,7 +615,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.SugarFormatting", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -442,7 +626,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("apex/log", func(b *testing.B) {
-		logger := newApexLog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newApexLogTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -451,7 +637,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("go-kit/kit/log", func(b *testing.B) {
-		logger := newKitLog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newKitLogTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -460,7 +648,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("inconshreveable/log15", func(b *testing.B) {
-		logger := newLog15()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newLog15To(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -469,7 +659,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("sirupsen/logrus", func(b *testing.B) {
-		logger := newLogrus()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newLogrusTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -478,7 +670,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("stdlib.Println", func(b *testing.B) {
-		logger := log.New(io.Discard, "", log.LstdFlags)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := log.New(d, "", log.LstdFlags)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -487,7 +681,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("stdlib.Printf", func(b *testing.B) {
-		logger := log.New(io.Discard, "", log.LstdFlags)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := log.New(d, "", log.LstdFlags)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -496,7 +692,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := newZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZerologTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -505,7 +703,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Formatting", func(b *testing.B) {
-		logger := newZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZerologTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -514,7 +714,9 @@ func BenchmarkWithoutFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Check", func(b *testing.B) {
-		logger := newZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZerologTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -530,6 +732,7 @@ func BenchmarkWithContext(b *testing.B) {
 
 	b.Logf("Logging with some accumulated context.")
 	b.Run("Logy console", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard, Enabled: true}})
 
@@ -555,6 +758,7 @@ func BenchmarkWithContext(b *testing.B) {
 	})
 
 	b.Run("Logy", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard, Enabled: true, Format: "%d %p %c : %m%s%n", Json: &logy.JsonConfig{
 			Enabled: true,
@@ -582,7 +786,9 @@ func BenchmarkWithContext(b *testing.B) {
 	})
 
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := fakeZerologContext(newZerolog().With()).Logger()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := fakeZerologContext(newZerologTo(d).With()).Logger()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -591,6 +797,7 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Logy.Formatting", func(b *testing.B) {
+		defer reportScenario(b, nil)()
 		logger := logy.Get()
 		_ = logy.LoadConfig(&logy.Config{Level: logy.LevelDebug, Console: &logy.ConsoleConfig{Target: logy.TargetDiscard, Enabled: true, Format: "%d %p %c : %m%s%n"}})
 
@@ -616,7 +823,9 @@ func BenchmarkWithContext(b *testing.B) {
 	})
 
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...)
 
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
@@ -626,7 +835,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -637,7 +848,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -646,7 +859,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.SugarFormatting", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -655,7 +870,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Formatting", func(b *testing.B) {
-		logger := fakeZerologContext(newZerolog().With()).Logger()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := fakeZerologContext(newZerologTo(d).With()).Logger()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -665,7 +882,9 @@ func BenchmarkWithContext(b *testing.B) {
 	})
 
 	b.Run("exp/slog", func(b *testing.B) {
-		logger := slog.New(slog.NewTextHandler(io.Discard)).With("int", _tenInts[0], "ints", _tenInts, "string", _tenStrings[0], "strings", _tenStrings, "time", _tenTimes[0], "times", _tenTimes,
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := slog.New(slog.NewTextHandler(d)).With("int", _tenInts[0], "ints", _tenInts, "string", _tenStrings[0], "strings", _tenStrings, "time", _tenTimes[0], "times", _tenTimes,
 			"user1", _oneUser, "user2", _oneUser, "users", _tenUsers, "error", errExample)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
@@ -675,7 +894,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -684,7 +905,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -695,7 +918,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.CheckSampled", func(b *testing.B) {
-		logger := newSampledLogger(zap.DebugLevel).With(fakeFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newSampledLoggerTo(d, zap.DebugLevel).With(fakeFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			i := 0
@@ -708,7 +933,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -717,7 +944,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("Zap.SugarFormatting", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).With(fakeFields()...).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).With(fakeFields()...).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -726,7 +955,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("apex/log", func(b *testing.B) {
-		logger := newApexLog().WithFields(fakeApexFields())
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newApexLogTo(d).WithFields(fakeApexFields())
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -735,7 +966,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("go-kit/kit/log", func(b *testing.B) {
-		logger := newKitLog(fakeSugarFields()...)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newKitLogTo(d, fakeSugarFields()...)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -744,7 +977,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("inconshreveable/log15", func(b *testing.B) {
-		logger := newLog15().New(fakeSugarFields())
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newLog15To(d).New(fakeSugarFields())
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -753,7 +988,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("sirupsen/logrus", func(b *testing.B) {
-		logger := newLogrus().WithFields(fakeLogrusFields())
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newLogrusTo(d).WithFields(fakeLogrusFields())
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -762,7 +999,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := fakeZerologContext(newZerolog().With()).Logger()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := fakeZerologContext(newZerologTo(d).With()).Logger()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -771,7 +1010,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Check", func(b *testing.B) {
-		logger := fakeZerologContext(newZerolog().With()).Logger()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := fakeZerologContext(newZerologTo(d).With()).Logger()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -782,7 +1023,9 @@ func BenchmarkWithContext(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Formatting", func(b *testing.B) {
-		logger := fakeZerologContext(newZerolog().With()).Logger()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := fakeZerologContext(newZerologTo(d).With()).Logger()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -801,7 +1044,7 @@ func BenchmarkAddingFields(b *testing.B) {
 	b.Logf("Logging with additional context at each log site.")
 
 	b.Run("Logy.Formatting", func(b *testing.B) {
-
+		defer reportScenario(b, nil)()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -811,7 +1054,9 @@ func BenchmarkAddingFields(b *testing.B) {
 	})
 
 	b.Run("Zap", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -820,7 +1065,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Check", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -831,7 +1078,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.CheckSampled", func(b *testing.B) {
-		logger := newSampledLogger(zap.DebugLevel)
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newSampledLoggerTo(d, zap.DebugLevel)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			i := 0
@@ -844,7 +1093,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("Zap.Sugar", func(b *testing.B) {
-		logger := newZapLogger(zap.DebugLevel).Sugar()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZapLoggerTo(d, zap.DebugLevel).Sugar()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -853,7 +1104,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("apex/log", func(b *testing.B) {
-		logger := newApexLog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newApexLogTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -862,7 +1115,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("go-kit/kit/log", func(b *testing.B) {
-		logger := newKitLog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newKitLogTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -871,7 +1126,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("inconshreveable/log15", func(b *testing.B) {
-		logger := newLog15()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newLog15To(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -880,7 +1137,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("sirupsen/logrus", func(b *testing.B) {
-		logger := newLogrus()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newLogrusTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -889,7 +1148,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog", func(b *testing.B) {
-		logger := newZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZerologTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -898,7 +1159,9 @@ func BenchmarkAddingFields(b *testing.B) {
 		})
 	})
 	b.Run("rs/zerolog.Check", func(b *testing.B) {
-		logger := newZerolog()
+		d := &Discarder{}
+		defer reportScenario(b, d)()
+		logger := newZerologTo(d)
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -0,0 +1,256 @@
+package benchmarks
+
+import (
+	"io"
+	stdlog "log"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/diode"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// SlowWriter is a Discarder that takes its time: every write waits Latency
+// plus a random duration up to Jitter, and every StallEvery-th write stalls for
+// an extra Stall. Once an error is set with SetError, writes fail with it.
+type SlowWriter struct {
+	Discarder
+	Latency    time.Duration
+	Jitter     time.Duration
+	StallEvery int64
+	Stall      time.Duration
+
+	writes      int64
+	inflight    int64
+	maxInflight int64
+}
+
+func (w *SlowWriter) Write(b []byte) (int, error) {
+	n := atomic.AddInt64(&w.writes, 1)
+	in := atomic.AddInt64(&w.inflight, 1)
+	defer atomic.AddInt64(&w.inflight, -1)
+	for {
+		max := atomic.LoadInt64(&w.maxInflight)
+		if in <= max || atomic.CompareAndSwapInt64(&w.maxInflight, max, in) {
+			break
+		}
+	}
+
+	d := w.Latency
+	if w.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(w.Jitter)))
+	}
+	if w.StallEvery > 0 && n%w.StallEvery == 0 {
+		d += w.Stall
+	}
+	if d > 0 {
+		time.Sleep(d)
+	}
+
+	return w.Discarder.Write(b)
+}
+
+// MaxInflight returns the highest number of writes seen in progress at the
+// same time. A logger serializing its writes behind a lock never gets past 1.
+func (w *SlowWriter) MaxInflight() int64 {
+	return atomic.LoadInt64(&w.maxInflight)
+}
+
+var _slowWriterProfiles = []struct {
+	name string
+	new  func() *SlowWriter
+}{
+	{"Latency", func() *SlowWriter {
+		return &SlowWriter{Latency: 20 * time.Microsecond}
+	}},
+	{"Jitter", func() *SlowWriter {
+		return &SlowWriter{Latency: 10 * time.Microsecond, Jitter: 40 * time.Microsecond}
+	}},
+	{"Stalls", func() *SlowWriter {
+		return &SlowWriter{StallEvery: 500, Stall: 5 * time.Millisecond}
+	}},
+	{"Errors", func() *SlowWriter {
+		w := &SlowWriter{}
+		w.SetError(errExample)
+		return w
+	}},
+}
+
+// asyncLogger is a library set up with its buffered or asynchronous writer.
+type asyncLogger struct {
+	name string
+	// new returns a function logging a single record into w, a function
+	// returning how many records were dropped so far and a function flushing
+	// and stopping the logger.
+	new func(w io.Writer) (log func(), dropped func() int64, stop func())
+}
+
+func asyncLoggers() []asyncLogger {
+	return []asyncLogger{
+		{
+			name: "Zap.Buffered",
+			new: func(w io.Writer) (func(), func() int64, func()) {
+				ws := &zapcore.BufferedWriteSyncer{WS: zapcore.AddSync(w), Size: 256 << 10, FlushInterval: 30 * time.Second}
+				logger := newZapLoggerTo(ws, zap.DebugLevel).With(fakeFields()...)
+				return func() {
+					logger.Info(getMessage(0))
+				}, func() int64 { return 0 }, func() { _ = ws.Stop() }
+			},
+		},
+		{
+			name: "rs/zerolog.Diode",
+			new: func(w io.Writer) (func(), func() int64, func()) {
+				var dropped int64
+				dw := diode.NewWriter(w, 1000, 10*time.Millisecond, func(missed int) {
+					atomic.AddInt64(&dropped, int64(missed))
+				})
+				logger := fakeZerologContext(newZerologTo(dw).With()).Logger()
+				log := func() {
+					logger.Info().Msg(getMessage(0))
+				}
+				return log, func() int64 {
+					return atomic.LoadInt64(&dropped)
+				}, func() { _ = dw.Close() }
+			},
+		},
+		{
+			name: "inconshreveable/log15.Buffered",
+			new: func(w io.Writer) (func(), func() int64, func()) {
+				// log15.BufferedHandler can't be stopped, this is the same
+				// handler with a way to flush it and stop its goroutine.
+				recs := make(chan *log15.Record, 1000)
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					handler := log15.StreamHandler(w, log15.JsonFormat())
+					for r := range recs {
+						_ = handler.Log(r)
+					}
+				}()
+				logger := log15.New(fakeSugarFields()...)
+				logger.SetHandler(log15.ChannelHandler(recs))
+				log := func() {
+					logger.Info(getMessage(0))
+				}
+				return log, func() int64 { return 0 }, func() {
+					close(recs)
+					<-done
+				}
+			},
+		},
+	}
+}
+
+// latencyRecorder collects the call-site latencies measured by the goroutines
+// of a parallel benchmark.
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func (r *latencyRecorder) run(pb *testing.PB, log func()) {
+	samples := make([]time.Duration, 0, 1024)
+	for pb.Next() {
+		start := time.Now()
+		log()
+		samples = append(samples, time.Since(start))
+	}
+
+	r.mu.Lock()
+	r.samples = append(r.samples, samples...)
+	r.mu.Unlock()
+}
+
+func (r *latencyRecorder) report(b *testing.B) {
+	if len(r.samples) == 0 {
+		return
+	}
+	sort.Slice(r.samples, func(i, j int) bool { return r.samples[i] < r.samples[j] })
+	percentile := func(p float64) float64 {
+		return float64(r.samples[int(p*float64(len(r.samples)-1))])
+	}
+	b.ReportMetric(percentile(0.5), "p50-ns")
+	b.ReportMetric(percentile(0.99), "p99-ns")
+	b.ReportMetric(percentile(1), "max-ns")
+}
+
+// silenceStderr points os.Stderr and the standard logger at the null device
+// until the returned function is called, for the libraries reporting every
+// failed write there.
+func silenceStderr(tb testing.TB) func() {
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stderr, output := os.Stderr, stdlog.Writer()
+	os.Stderr = null
+	stdlog.SetOutput(null)
+	return func() {
+		os.Stderr = stderr
+		stdlog.SetOutput(output)
+		_ = null.Close()
+	}
+}
+
+func BenchmarkSlowWriter(b *testing.B) {
+	b.Logf("Logging into a writer with latency, jitter, stalls or failing writes.")
+	for _, profile := range _slowWriterProfiles {
+		profile := profile
+		for _, l := range writerLoggers() {
+			// Logy.Pipe writes to a pipe the slow writer is behind, the way
+			// logy writes to a stalled stdout: it blocks once the pipe is
+			// full. The writes reaching the slow writer are the pipe's, so
+			// there are fewer of them than records and never two at once.
+			l := l
+			b.Run(profile.name+"/"+l.name, func(b *testing.B) {
+				if profile.name == "Errors" {
+					defer silenceStderr(b)()
+				}
+				w := profile.new()
+				defer reportScenario(b, &w.Discarder)()
+				log, stop := l.new(w)
+				rec := &latencyRecorder{}
+
+				b.SetParallelism(4)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					rec.run(pb, log)
+				})
+				b.StopTimer()
+				stop()
+				rec.report(b)
+				b.ReportMetric(float64(w.MaxInflight()), "max-inflight")
+			})
+		}
+		for _, l := range asyncLoggers() {
+			l := l
+			b.Run(profile.name+"/"+l.name, func(b *testing.B) {
+				if profile.name == "Errors" {
+					defer silenceStderr(b)()
+				}
+				w := profile.new()
+				defer reportScenario(b, &w.Discarder)()
+				log, dropped, stop := l.new(w)
+				rec := &latencyRecorder{}
+
+				b.SetParallelism(4)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					rec.run(pb, log)
+				})
+				b.StopTimer()
+				stop()
+				rec.report(b)
+				b.ReportMetric(float64(w.MaxInflight()), "max-inflight")
+				b.ReportMetric(float64(dropped())/float64(b.N), "dropped/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package benchmarks
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fileSink counts the Write calls made on an *os.File, each of which is a
+// write syscall. A library making more than one per record doesn't write its
+// records atomically when the file is shared.
+type fileSink struct {
+	writes int64
+	f      *os.File
+	close  func()
+}
+
+func (s *fileSink) Write(b []byte) (int, error) {
+	atomic.AddInt64(&s.writes, 1)
+	return s.f.Write(b)
+}
+
+func (s *fileSink) Writes() int64 {
+	return atomic.LoadInt64(&s.writes)
+}
+
+// newPipeSink returns a sink writing into a pipe drained by another goroutine.
+// The pipe is put in blocking mode, a write into a full non-blocking pipe would
+// take several syscalls.
+func newPipeSink(tb testing.TB) *fileSink {
+	r, w, err := os.Pipe()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_ = w.Fd()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(io.Discard, r)
+	}()
+
+	return &fileSink{f: w, close: func() {
+		_ = w.Close()
+		<-done
+		_ = r.Close()
+	}}
+}
+
+// newAppendSink returns a sink writing into a temporary file opened with
+// O_APPEND, as log files shared by several processes are.
+func newAppendSink(tb testing.TB) *fileSink {
+	path := filepath.Join(tb.TempDir(), "app.log")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return &fileSink{f: f, close: func() { _ = f.Close() }}
+}
+
+var _fileSinks = []struct {
+	name string
+	new  func(tb testing.TB) *fileSink
+}{
+	{"Pipe", newPipeSink},
+	{"AppendFile", newAppendSink},
+}
+
+// writeSyscalls returns the write syscalls made so far according to ioFile,
+// /proc/self/io for the process or /proc/thread-self/io for the calling thread.
+// The test is skipped where there is no such file.
+func writeSyscalls(tb testing.TB, ioFile string) int64 {
+	b, err := os.ReadFile(ioFile)
+	if err != nil {
+		tb.Skip("logy's writes are counted from /proc: ", err)
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if strings.HasPrefix(line, "syscw: ") {
+			n, err := strconv.ParseInt(strings.TrimPrefix(line, "syscw: "), 10, 64)
+			if err != nil {
+				tb.Fatal(err)
+			}
+			return n
+		}
+	}
+	tb.Skip("logy's writes are counted from /proc, but ", ioFile, " has no syscw")
+	return 0
+}
+
+// sinkLogger logs into a fileSink, with a function telling how many write
+// syscalls reached the sink.
+type sinkLogger struct {
+	name string
+	new  func(tb testing.TB, s *fileSink) (log func(), stop func(), syscalls func() int64)
+}
+
+// sinkLoggers returns the writerLoggers set up on a fileSink. logy can't be
+// given the sink as a writer, it writes straight into the sink's file and its
+// syscalls are counted by the kernel instead, from ioFile as in writeSyscalls.
+// The count of the process includes the few writes of the runtime itself.
+func sinkLoggers(ioFile string) []sinkLogger {
+	loggers := []sinkLogger{{"Logy", func(tb testing.TB, s *fileSink) (func(), func(), func() int64) {
+		log, stop := newLogyFileLogger(s.f)
+		start := writeSyscalls(tb, ioFile)
+		return log, stop, func() int64 { return writeSyscalls(tb, ioFile) - start }
+	}}}
+	for _, l := range writerLoggers() {
+		if l.piped {
+			continue
+		}
+		l := l
+		loggers = append(loggers, sinkLogger{l.name, func(_ testing.TB, s *fileSink) (func(), func(), func() int64) {
+			log, stop := l.new(s)
+			return log, stop, s.Writes
+		}})
+	}
+	return loggers
+}
+
+func BenchmarkWriteSyscalls(b *testing.B) {
+	b.Logf("Logging into a pipe and an O_APPEND file, counting the write syscalls.")
+	for _, sink := range _fileSinks {
+		sink := sink
+		for _, l := range sinkLoggers("/proc/self/io") {
+			l := l
+			b.Run(sink.name+"/"+l.name, func(b *testing.B) {
+				defer reportScenario(b, nil)()
+				s := sink.new(b)
+				log, stop, syscalls := l.new(b, s)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						log()
+					}
+				})
+				b.StopTimer()
+				n := syscalls()
+				stop()
+				s.close()
+				b.ReportMetric(float64(n)/float64(b.N), "syscalls/op")
+			})
+		}
+	}
+}
+
+func TestWriteSyscalls(t *testing.T) {
+	const records = 100
+	for _, sink := range _fileSinks {
+		sink := sink
+		// logy logs from the test's goroutine, whose thread alone is counted.
+		for _, l := range sinkLoggers("/proc/thread-self/io") {
+			l := l
+			t.Run(sink.name+"/"+l.name, func(t *testing.T) {
+				runtime.LockOSThread()
+				defer runtime.UnlockOSThread()
+				s := sink.new(t)
+				log, stop, syscalls := l.new(t, s)
+				for i := 0; i < records; i++ {
+					log()
+				}
+				n := syscalls()
+				stop()
+				s.close()
+
+				if n != records {
+					t.Errorf("got %d write calls for %d records, records are split across writes", n, records)
+				}
+			})
+		}
+	}
+}
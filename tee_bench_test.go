@@ -0,0 +1,480 @@
+package benchmarks
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	apex "github.com/apex/log"
+	apexjson "github.com/apex/log/handlers/json"
+	apexlevel "github.com/apex/log/handlers/level"
+	"github.com/apex/log/handlers/logfmt"
+	"github.com/apex/log/handlers/multi"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// teeSink describes one output of a fan-out logger. Levels are expressed as
+// zap levels and mapped onto each library's own levels.
+type teeSink struct {
+	json  bool
+	level zapcore.Level
+	// w receives the sink's output; nil means discard.
+	w io.Writer
+}
+
+// _teeSinks are the sinks used by the fan-out scenarios, the first n of them
+// being configured for n sinks. The first sink is always the most verbose one,
+// since libraries like logrus derive the logger level from it.
+var _teeSinks = []teeSink{
+	{json: true, level: zap.DebugLevel},
+	{json: false, level: zap.InfoLevel},
+	{json: true, level: zap.WarnLevel},
+	{json: false, level: zap.DebugLevel},
+	{json: true, level: zap.ErrorLevel},
+}
+
+var _teeSinkCounts = []int{1, 2, 3, 5}
+
+func fakeTeeSinks(n int) []teeSink {
+	sinks := make([]teeSink, n)
+	copy(sinks, _teeSinks)
+	return sinks
+}
+
+func orDiscard(w io.Writer) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+	return w
+}
+
+// zerologLevelWriter only writes the events at or above its level.
+type zerologLevelWriter struct {
+	w     io.Writer
+	level zerolog.Level
+}
+
+func (lw zerologLevelWriter) Write(p []byte) (int, error) {
+	return lw.w.Write(p)
+}
+
+func (lw zerologLevelWriter) WriteLevel(l zerolog.Level, p []byte) (int, error) {
+	if l < lw.level {
+		return len(p), nil
+	}
+	return lw.w.Write(p)
+}
+
+// logrusSinkHook writes the entries of its levels to an extra sink, which is
+// how logrus loggers usually get more than one output.
+type logrusSinkHook struct {
+	w         io.Writer
+	formatter logrus.Formatter
+	levels    []logrus.Level
+}
+
+func (h *logrusSinkHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *logrusSinkHook) Fire(e *logrus.Entry) error {
+	b, err := h.formatter.Format(e)
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(b)
+	return err
+}
+
+// teeHandler is a slog handler passing each record to all of its handlers.
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(l slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(r slog.Record) error {
+	var err error
+	for _, h := range t {
+		if !h.Enabled(r.Level) {
+			continue
+		}
+		if e := h.Handle(r); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (t teeHandler) WithAttrs(as []slog.Attr) slog.Handler {
+	t2 := make(teeHandler, len(t))
+	for i, h := range t {
+		t2[i] = h.WithAttrs(as)
+	}
+	return t2
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	t2 := make(teeHandler, len(t))
+	for i, h := range t {
+		t2[i] = h.WithGroup(name)
+	}
+	return t2
+}
+
+func kitTee(loggers ...log.Logger) log.Logger {
+	return log.LoggerFunc(func(keyvals ...interface{}) error {
+		var err error
+		for _, l := range loggers {
+			if e := l.Log(keyvals...); e != nil {
+				err = e
+			}
+		}
+		return err
+	})
+}
+
+func kitAllow(lvl zapcore.Level) level.Option {
+	switch lvl {
+	case zap.DebugLevel:
+		return level.AllowDebug()
+	case zap.InfoLevel:
+		return level.AllowInfo()
+	case zap.WarnLevel:
+		return level.AllowWarn()
+	default:
+		return level.AllowError()
+	}
+}
+
+func logyLevel(lvl zapcore.Level) logy.Level {
+	switch lvl {
+	case zap.DebugLevel:
+		return logy.LevelDebug
+	case zap.InfoLevel:
+		return logy.LevelInfo
+	case zap.WarnLevel:
+		return logy.LevelWarn
+	default:
+		return logy.LevelError
+	}
+}
+
+type teeLogger struct {
+	name string
+	// build returns functions logging one record at info and at error level
+	// to all the sinks, or nil functions if the library can't be configured
+	// with that many sinks.
+	build func(sinks []teeSink) (info, errorf, stop func())
+}
+
+func teeLoggers() []teeLogger {
+	return []teeLogger{
+		{
+			name: "Logy",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				// logy has one handler per kind of output; the only local ones
+				// are the console and the file handler, so it stops at two sinks.
+				// The file sink writes to a temporary file whose content is copied
+				// into the sink writer on stop.
+				if len(sinks) > 2 {
+					return nil, nil, nil
+				}
+
+				console := &logy.ConsoleConfig{Enabled: true, Target: logy.TargetDiscard, Level: logyLevel(sinks[0].level)}
+				if sinks[0].json {
+					console.Json = &logy.JsonConfig{Enabled: true}
+				}
+				config := &logy.Config{Level: logy.LevelDebug, IncludeCaller: false, Handlers: logy.Handlers{"console"}, Console: console}
+
+				var file string
+				if len(sinks) > 1 {
+					file = os.DevNull
+					if sinks[1].w != nil {
+						dir, err := os.MkdirTemp("", "logy-tee")
+						if err != nil {
+							panic(err)
+						}
+						file = filepath.Join(dir, "tee.log")
+					}
+					config.Handlers = append(config.Handlers, "file")
+					config.File = &logy.FileConfig{Enabled: true, Path: filepath.Dir(file), Name: filepath.Base(file), Level: logyLevel(sinks[1].level), Format: "%d %p %c : %m%s%n"}
+					if sinks[1].json {
+						config.File.Json = &logy.JsonConfig{Enabled: true}
+					}
+				}
+
+				stopConsole := func() {}
+				if sinks[0].w != nil {
+					stopConsole = redirectLogy(sinks[0].w, config)
+				} else {
+					_ = logy.LoadConfig(config)
+				}
+
+				logger := logy.Get()
+				stop := func() {
+					stopConsole()
+					if len(sinks) > 1 && sinks[1].w != nil {
+						f, err := os.Open(file)
+						if err != nil {
+							panic(err)
+						}
+						_, _ = io.Copy(sinks[1].w, f)
+						_ = f.Close()
+						_ = os.RemoveAll(filepath.Dir(file))
+					}
+				}
+				info := func() {
+					logger.Info(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error(getMessage(0))
+				}
+				return info, errorf, stop
+			},
+		},
+		{
+			name: "exp/slog",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				handlers := make(teeHandler, len(sinks))
+				for i, s := range sinks {
+					opts := slog.HandlerOptions{Level: slog.Level(4 * s.level)}
+					if s.json {
+						handlers[i] = opts.NewJSONHandler(orDiscard(s.w))
+					} else {
+						handlers[i] = opts.NewTextHandler(orDiscard(s.w))
+					}
+				}
+				logger := slog.New(handlers)
+				info := func() {
+					logger.Info(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error(getMessage(0), nil)
+				}
+				return info, errorf, func() {}
+			},
+		},
+		{
+			name: "Zap",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				ec := zap.NewProductionEncoderConfig()
+				ec.EncodeDuration = zapcore.NanosDurationEncoder
+				ec.EncodeTime = zapcore.ISO8601TimeEncoder
+
+				cores := make([]zapcore.Core, len(sinks))
+				for i, s := range sinks {
+					enc := zapcore.NewConsoleEncoder(ec)
+					if s.json {
+						enc = zapcore.NewJSONEncoder(ec)
+					}
+					cores[i] = zapcore.NewCore(enc, zapcore.AddSync(orDiscard(s.w)), s.level)
+				}
+				logger := zap.New(zapcore.NewTee(cores...))
+				info := func() {
+					logger.Info(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error(getMessage(0))
+				}
+				return info, errorf, func() {}
+			},
+		},
+		{
+			name: "rs/zerolog",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				writers := make([]io.Writer, len(sinks))
+				for i, s := range sinks {
+					w := orDiscard(s.w)
+					if !s.json {
+						w = zerolog.ConsoleWriter{Out: w, NoColor: true}
+					}
+					writers[i] = zerologLevelWriter{w: w, level: zerolog.Level(s.level + 1)}
+				}
+				logger := newZerologTo(zerolog.MultiLevelWriter(writers...))
+				info := func() {
+					logger.Info().Msg(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error().Msg(getMessage(0))
+				}
+				return info, errorf, func() {}
+			},
+		},
+		{
+			name: "apex/log",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				handlers := make([]apex.Handler, len(sinks))
+				for i, s := range sinks {
+					var h apex.Handler = logfmt.New(orDiscard(s.w))
+					if s.json {
+						h = apexjson.New(orDiscard(s.w))
+					}
+					handlers[i] = apexlevel.New(h, apex.Level(s.level+1))
+				}
+				logger := &apex.Logger{
+					Handler: multi.New(handlers...),
+					Level:   apex.DebugLevel,
+				}
+				info := func() {
+					logger.Info(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error(getMessage(0))
+				}
+				return info, errorf, func() {}
+			},
+		},
+		{
+			name: "go-kit/kit/log",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				loggers := make([]log.Logger, len(sinks))
+				for i, s := range sinks {
+					l := log.NewLogfmtLogger(orDiscard(s.w))
+					if s.json {
+						l = log.NewJSONLogger(orDiscard(s.w))
+					}
+					loggers[i] = level.NewFilter(l, kitAllow(s.level))
+				}
+				logger := kitTee(loggers...)
+				infoLogger, errorLogger := level.Info(logger), level.Error(logger)
+				info := func() {
+					_ = infoLogger.Log("msg", getMessage(0))
+				}
+				errorf := func() {
+					_ = errorLogger.Log("msg", getMessage(0))
+				}
+				return info, errorf, func() {}
+			},
+		},
+		{
+			name: "inconshreveable/log15",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				handlers := make([]log15.Handler, len(sinks))
+				for i, s := range sinks {
+					format := log15.LogfmtFormat()
+					if s.json {
+						format = log15.JsonFormat()
+					}
+					handlers[i] = log15.LvlFilterHandler(log15.Lvl(3-s.level), log15.StreamHandler(orDiscard(s.w), format))
+				}
+				logger := log15.New()
+				logger.SetHandler(log15.MultiHandler(handlers...))
+				info := func() {
+					logger.Info(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error(getMessage(0))
+				}
+				return info, errorf, func() {}
+			},
+		},
+		{
+			name: "sirupsen/logrus",
+			build: func(sinks []teeSink) (func(), func(), func()) {
+				formatter := func(json bool) logrus.Formatter {
+					if json {
+						return new(logrus.JSONFormatter)
+					}
+					return &logrus.TextFormatter{DisableColors: true}
+				}
+
+				logger := newLogrusTo(orDiscard(sinks[0].w))
+				logger.Formatter = formatter(sinks[0].json)
+				logger.Level = logrus.Level(4 - sinks[0].level)
+				for _, s := range sinks[1:] {
+					logger.AddHook(&logrusSinkHook{
+						w:         orDiscard(s.w),
+						formatter: formatter(s.json),
+						levels:    logrus.AllLevels[:5-s.level],
+					})
+				}
+				info := func() {
+					logger.Info(getMessage(0))
+				}
+				errorf := func() {
+					logger.Error(getMessage(0))
+				}
+				return info, errorf, func() {}
+			},
+		},
+	}
+}
+
+func BenchmarkTee(b *testing.B) {
+	b.Logf("Logging to several sinks with different levels and formats.")
+	for _, l := range teeLoggers() {
+		l := l
+		for _, n := range _teeSinkCounts {
+			n := n
+			b.Run(l.name+"/"+strconv.Itoa(n), func(b *testing.B) {
+				defer reportScenario(b, nil)()
+				info, _, stop := l.build(fakeTeeSinks(n))
+				if info == nil {
+					b.Skipf("%s can't write to %d sinks", l.name, n)
+				}
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						info()
+					}
+				})
+				b.StopTimer()
+				stop()
+			})
+		}
+	}
+}
+
+func TestTeeSinks(t *testing.T) {
+	const records = 10
+	for _, l := range teeLoggers() {
+		l := l
+		for _, n := range _teeSinkCounts {
+			n := n
+			t.Run(l.name+"/"+strconv.Itoa(n), func(t *testing.T) {
+				sinks := fakeTeeSinks(n)
+				counters := make([]*lineCounter, n)
+				for i := range sinks {
+					counters[i] = &lineCounter{}
+					sinks[i].w = counters[i]
+				}
+
+				info, errorf, stop := l.build(sinks)
+				if info == nil {
+					t.Skipf("%s can't write to %d sinks", l.name, n)
+				}
+				for i := 0; i < records; i++ {
+					info()
+					errorf()
+				}
+				stop()
+
+				for i, s := range sinks {
+					want := int64(records)
+					if s.level <= zap.InfoLevel {
+						want += records
+					}
+					if got := counters[i].Lines(); got != want {
+						t.Errorf("sink %d received %d records, want %d", i, got, want)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apex/log"
+	kitlog "github.com/go-kit/log"
+	"github.com/procyon-projects/logy"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+const _customTimeLayout = "02/Jan/2006:15:04:05.000 -0700"
+
+// timeFormat is a way of writing the record time. Layout formats write a
+// string, epoch formats an integer and a format with neither writes no time.
+type timeFormat struct {
+	name    string
+	layout  string
+	epoch   func(t time.Time) int64
+	zerolog string // the zerolog.TimeFieldFormat of the format
+}
+
+func (f timeFormat) none() bool {
+	return f.layout == "" && f.epoch == nil
+}
+
+// value returns the time as written with the format.
+func (f timeFormat) value(t time.Time) interface{} {
+	if f.epoch != nil {
+		return f.epoch(t)
+	}
+	return t.Format(f.layout)
+}
+
+var _timeFormats = []timeFormat{
+	{name: "None"},
+	{name: "UnixSeconds", epoch: time.Time.Unix, zerolog: zerolog.TimeFormatUnix},
+	{name: "UnixMillis", epoch: time.Time.UnixMilli, zerolog: zerolog.TimeFormatUnixMs},
+	{name: "UnixNanos", epoch: time.Time.UnixNano, zerolog: zerolog.TimeFormatUnixNano},
+	{name: "RFC3339", layout: time.RFC3339, zerolog: time.RFC3339},
+	{name: "RFC3339Nano", layout: time.RFC3339Nano, zerolog: time.RFC3339Nano},
+	{name: "Layout", layout: _customTimeLayout, zerolog: _customTimeLayout},
+}
+
+// epochFormatter writes the time of logrus records as an integer, which its
+// JSON formatter only knows as a layout.
+type epochFormatter struct {
+	logrus.Formatter
+	epoch func(t time.Time) int64
+}
+
+func (f epochFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	b, err := f.Formatter.Format(e)
+	if err != nil || len(b) < 2 {
+		return b, err
+	}
+	out := make([]byte, 0, len(b)+32)
+	out = append(out, `{"time":`...)
+	out = strconv.AppendInt(out, f.epoch(e.Time), 10)
+	if b[1] != '}' {
+		out = append(out, ',')
+	}
+	return append(out, b[1:]...), nil
+}
+
+// timeApexHandler writes the layout of apex's JSON handler, with the
+// timestamp in the given format.
+type timeApexHandler struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	format timeFormat
+}
+
+func (h *timeApexHandler) HandleLog(e *log.Entry) error {
+	record := struct {
+		Fields    log.Fields  `json:"fields"`
+		Level     log.Level   `json:"level"`
+		Timestamp interface{} `json:"timestamp,omitempty"`
+		Message   string      `json:"message"`
+	}{Fields: e.Fields, Level: e.Level, Message: e.Message}
+	if !h.format.none() {
+		record.Timestamp = h.format.value(e.Timestamp)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.enc.Encode(record)
+}
+
+// timeLog15Format is log15's JsonFormat with the time in the given format.
+func timeLog15Format(format timeFormat) log15.Format {
+	return log15.FormatFunc(func(r *log15.Record) []byte {
+		record := make(map[string]interface{}, len(r.Ctx)/2+3)
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			if key, ok := r.Ctx[i].(string); ok {
+				record[key] = r.Ctx[i+1]
+			}
+		}
+		if !format.none() {
+			record[r.KeyNames.Time] = format.value(r.Time)
+		}
+		record[r.KeyNames.Lvl] = r.Lvl.String()
+		record[r.KeyNames.Msg] = r.Msg
+		b, _ := json.Marshal(record)
+		return append(b, '\n')
+	})
+}
+
+// timeLogger logs a record with the time in one format. setup, if set, is
+// run around the benchmark for libraries configured through globals. log is
+// nil for the formats a library can't write.
+type timeLogger struct {
+	name  string
+	setup func(tb testing.TB) (restore func())
+	log   func()
+}
+
+// logyTimeConsole returns logy's console config writing the time in format.
+// logy's JSON time layout isn't configurable, so its text console is used,
+// whose %d pattern takes a layout. It has no epoch format, nil is returned
+// for those.
+func logyTimeConsole(format timeFormat) *logy.ConsoleConfig {
+	if format.epoch != nil {
+		return nil
+	}
+	pattern := "%p %c : %m%n"
+	if !format.none() {
+		pattern = "%d{" + format.layout + "} " + pattern
+	}
+	return &logy.ConsoleConfig{Enabled: true, Format: pattern, Json: &logy.JsonConfig{Enabled: false}}
+}
+
+func timeLoggers(w io.Writer, format timeFormat) []timeLogger {
+	logyText := timeLogger{name: "Logy.Text"}
+	if console := logyTimeConsole(format); console != nil {
+		logyLogger := logy.Get()
+		logyText.setup = func(tb testing.TB) func() {
+			// The discard target may not render the time at all.
+			return loadLogyIntoDevNull(tb, &logy.Config{Level: logy.LevelDebug, Console: console})
+		}
+		logyText.log = func() {
+			logyLogger.I(context.Background(), getMessage(0))
+		}
+	}
+	loggers := []timeLogger{logyText}
+
+	var opts slog.HandlerOptions
+	opts.ReplaceAttr = func(a slog.Attr) slog.Attr {
+		if a.Key != slog.TimeKey || a.Value.Kind() != slog.TimeKind {
+			return a
+		}
+		switch t := a.Value.Time(); {
+		case format.none():
+			return slog.Attr{}
+		case format.epoch != nil:
+			return slog.Int64(a.Key, format.epoch(t))
+		default:
+			return slog.String(a.Key, t.Format(format.layout))
+		}
+	}
+	slogLogger := slog.New(opts.NewJSONHandler(w))
+	loggers = append(loggers, timeLogger{name: "exp/slog", log: func() {
+		slogLogger.LogAttrs(slog.LevelInfo, getMessage(0))
+	}})
+
+	ec := zap.NewProductionEncoderConfig()
+	switch {
+	case format.none():
+		ec.TimeKey = zapcore.OmitKey
+	case format.epoch != nil:
+		ec.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) { enc.AppendInt64(format.epoch(t)) }
+	default:
+		ec.EncodeTime = zapcore.TimeEncoderOfLayout(format.layout)
+	}
+	zapLogger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(ec), zapcore.AddSync(w), zap.DebugLevel))
+	loggers = append(loggers, timeLogger{name: "Zap", log: func() {
+		zapLogger.Info(getMessage(0))
+	}})
+
+	zerologLogger := zerolog.New(w)
+	if !format.none() {
+		zerologLogger = zerologLogger.With().Timestamp().Logger()
+	}
+	loggers = append(loggers, timeLogger{name: "rs/zerolog", setup: func(testing.TB) func() {
+		timeFieldFormat := zerolog.TimeFieldFormat
+		zerolog.TimeFieldFormat = format.zerolog
+		return func() { zerolog.TimeFieldFormat = timeFieldFormat }
+	}, log: func() {
+		zerologLogger.Info().Msg(getMessage(0))
+	}})
+
+	apexLogger := &log.Logger{
+		Handler: &timeApexHandler{enc: json.NewEncoder(w), format: format},
+		Level:   log.DebugLevel,
+	}
+	loggers = append(loggers, timeLogger{name: "apex/log", log: func() {
+		apexLogger.Info(getMessage(0))
+	}})
+
+	kitLogger := kitlog.NewJSONLogger(w)
+	switch {
+	case format.epoch != nil:
+		kitLogger = kitlog.With(kitLogger, "ts", kitlog.Valuer(func() interface{} { return format.epoch(time.Now()) }))
+	case !format.none():
+		kitLogger = kitlog.With(kitLogger, "ts", kitlog.TimestampFormat(time.Now, format.layout))
+	}
+	loggers = append(loggers, timeLogger{name: "go-kit/kit/log", log: func() {
+		_ = kitLogger.Log("msg", getMessage(0))
+	}})
+
+	log15Logger := log15.New()
+	log15Logger.SetHandler(log15.StreamHandler(w, timeLog15Format(format)))
+	loggers = append(loggers, timeLogger{name: "inconshreveable/log15", log: func() {
+		log15Logger.Info(getMessage(0))
+	}})
+
+	formatter := &logrus.JSONFormatter{TimestampFormat: format.layout, DisableTimestamp: format.layout == ""}
+	logrusLogger := newLogrusTo(w)
+	logrusLogger.Formatter = formatter
+	if format.epoch != nil {
+		logrusLogger.Formatter = epochFormatter{formatter, format.epoch}
+	}
+	loggers = append(loggers, timeLogger{name: "sirupsen/logrus", log: func() {
+		logrusLogger.Info(getMessage(0))
+	}})
+
+	return loggers
+}
+
+func BenchmarkTimeFormats(b *testing.B) {
+	b.Logf("Logging a message with the record time in each format.")
+	d := &Discarder{}
+	for _, format := range _timeFormats {
+		for _, l := range timeLoggers(d, format) {
+			l := l
+			b.Run(format.name+"/"+l.name, func(b *testing.B) {
+				if l.log == nil {
+					b.Skipf("%s can't write the time as %s", l.name, format.name)
+				}
+				if l.setup != nil {
+					defer l.setup(b)()
+				}
+				defer reportScenario(b, d)()
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						l.log()
+					}
+				})
+			})
+		}
+	}
+}
+
+// _timeKeys are the keys the libraries write the record time under.
+var _timeKeys = []string{"time", "ts", "timestamp", "t"}
+
+// logyTextTime returns the time at the start of a record of logy's text
+// console, nil if the format has none.
+func logyTextTime(record string, format timeFormat) interface{} {
+	if format.none() {
+		return nil
+	}
+	parts := strings.SplitN(record, " ", strings.Count(format.layout, " ")+2)
+	return strings.Join(parts[:len(parts)-1], " ")
+}
+
+func TestTimeFormats(t *testing.T) {
+	for _, format := range _timeFormats {
+		w := &switchWriter{}
+		for _, l := range timeLoggers(w, format) {
+			var out bytes.Buffer
+			w.Writer = &out
+			var value interface{}
+			switch {
+			case l.log == nil:
+				t.Logf("%s can't write the time as %s", l.name, format.name)
+				continue
+			case l.name == "Logy.Text":
+				restore := redirectLogy(&out, &logy.Config{Level: logy.LevelDebug, Console: logyTimeConsole(format)})
+				l.log()
+				restore()
+				value = logyTextTime(out.String(), format)
+			default:
+				if l.setup != nil {
+					restore := l.setup(t)
+					l.log()
+					restore()
+				} else {
+					l.log()
+				}
+
+				var record map[string]interface{}
+				decoder := json.NewDecoder(&out)
+				decoder.UseNumber()
+				if err := decoder.Decode(&record); err != nil {
+					t.Fatalf("%s/%s wrote invalid JSON: %v", format.name, l.name, err)
+				}
+				for _, key := range _timeKeys {
+					if v, ok := record[key]; ok {
+						value = v
+					}
+				}
+			}
+
+			switch v := value.(type) {
+			case nil:
+				if !format.none() {
+					t.Errorf("%s/%s wrote no time", format.name, l.name)
+				}
+			case json.Number:
+				n, err := v.Int64()
+				if format.epoch == nil || err != nil || n == 0 {
+					t.Errorf("%s/%s wrote the time as %v", format.name, l.name, v)
+				} else if now := format.epoch(time.Now()); now < n || now-n > format.epoch(time.Unix(60, 0)) {
+					t.Errorf("%s/%s wrote %d, a minute or more off %d", format.name, l.name, n, now)
+				}
+			case string:
+				if format.layout == "" {
+					t.Errorf("%s/%s wrote the time as %q", format.name, l.name, v)
+				} else if _, err := time.Parse(format.layout, v); err != nil {
+					t.Errorf("%s/%s wrote the time as %q: %v", format.name, l.name, v, err)
+				}
+			default:
+				t.Errorf("%s/%s wrote the time as %v", format.name, l.name, v)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/procyon-projects/logy"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -114,20 +115,24 @@ func (u *user) String() string {
 }
 
 func newZapLogger(lvl zapcore.Level) *zap.Logger {
+	return newZapLoggerTo(&Discarder{}, lvl)
+}
+
+func newZapLoggerTo(w io.Writer, lvl zapcore.Level) *zap.Logger {
 	ec := zap.NewProductionEncoderConfig()
 	ec.EncodeDuration = zapcore.NanosDurationEncoder
 	ec.EncodeTime = zapcore.ISO8601TimeEncoder
 	enc := zapcore.NewJSONEncoder(ec)
 	return zap.New(zapcore.NewCore(
 		enc,
-		&Discarder{},
+		zapcore.AddSync(w),
 		lvl,
 	))
 }
 
-func newSampledLogger(lvl zapcore.Level) *zap.Logger {
+func newSampledLoggerTo(w io.Writer, lvl zapcore.Level) *zap.Logger {
 	return zap.New(zapcore.NewSamplerWithOptions(
-		newZapLogger(zap.DebugLevel).Core(),
+		newZapLoggerTo(w, zap.DebugLevel).Core(),
 		100*time.Millisecond,
 		10, // first
 		10, // thereafter
@@ -7,11 +7,15 @@ import (
 )
 
 func newZerolog() zerolog.Logger {
-	return zerolog.New(io.Discard).With().Timestamp().Logger()
+	return newZerologTo(io.Discard)
 }
 
-func newDisabledZerolog() zerolog.Logger {
-	return newZerolog().Level(zerolog.Disabled)
+func newZerologTo(w io.Writer) zerolog.Logger {
+	return zerolog.New(w).With().Timestamp().Logger()
+}
+
+func newDisabledZerolog(w io.Writer) zerolog.Logger {
+	return newZerologTo(w).Level(zerolog.Disabled)
 }
 
 func (u *user) MarshalZerologObject(e *zerolog.Event) {